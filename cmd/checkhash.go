@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/stackup-wallet/stackup-paymaster/internal/checkhash"
+)
+
+var checkHashIterations int
+
+var checkHashCmd = &cobra.Command{
+	Use:   "check-hash",
+	Short: "Checks the offline paymaster hash against the contract",
+	Long:  "The check-hash command will compare the offline paymaster hash with the result of getHash on every configured paymaster using randomized UserOperations.",
+	Run: func(cmd *cobra.Command, args []string) {
+		checkhash.Run(checkHashIterations)
+	},
+}
+
+func init() {
+	checkHashCmd.Flags().IntVarP(&checkHashIterations, "iterations", "n", 10, "number of random ops to check per paymaster")
+	rootCmd.AddCommand(checkHashCmd)
+}
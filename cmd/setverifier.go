@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/stackup-wallet/stackup-paymaster/internal/setverifier"
+)
+
+var (
+	setVerifierPaymaster string
+	setVerifierAddress   string
+)
+
+var setVerifierCmd = &cobra.Command{
+	Use:   "set-verifier",
+	Short: "Sets the verifier of a paymaster contract",
+	Long:  "The set-verifier command will submit a setVerifier transaction to the paymaster using the owner key from ERC4337_PAYMASTER_OWNER_KEY.",
+	Run: func(cmd *cobra.Command, args []string) {
+		setverifier.Run(common.HexToAddress(setVerifierPaymaster), common.HexToAddress(setVerifierAddress))
+	},
+}
+
+func init() {
+	setVerifierCmd.Flags().StringVar(&setVerifierPaymaster, "paymaster", "", "address of the paymaster contract")
+	setVerifierCmd.Flags().StringVar(&setVerifierAddress, "verifier", "", "address of the new verifier")
+	_ = setVerifierCmd.MarkFlagRequired("paymaster")
+	_ = setVerifierCmd.MarkFlagRequired("verifier")
+	rootCmd.AddCommand(setVerifierCmd)
+}
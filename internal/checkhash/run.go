@@ -0,0 +1,158 @@
+// Package checkhash compares the offline paymaster hash against the on-chain getHash function.
+package checkhash
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stackup-wallet/stackup-paymaster/internal/config"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+)
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatal(err)
+	}
+	return b
+}
+
+func randomInt(bits uint) *big.Int {
+	n, err := rand.Int(rand.Reader, big.NewInt(0).Lsh(big.NewInt(1), bits))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return n
+}
+
+func randomOp() *packedop.UserOperation {
+	return &packedop.UserOperation{
+		Sender:                        common.BytesToAddress(randomBytes(20)),
+		Nonce:                         randomInt(256),
+		Factory:                       common.BytesToAddress(randomBytes(20)),
+		FactoryData:                   randomBytes(100),
+		CallData:                      randomBytes(200),
+		CallGasLimit:                  randomInt(64),
+		VerificationGasLimit:          randomInt(64),
+		PreVerificationGas:            randomInt(64),
+		MaxFeePerGas:                  randomInt(64),
+		MaxPriorityFeePerGas:          randomInt(64),
+		PaymasterVerificationGasLimit: randomInt(64),
+		PaymasterPostOpGasLimit:       randomInt(64),
+		PaymasterData:                 randomBytes(100),
+		Signature:                     randomBytes(65),
+	}
+}
+
+func randomData(pm common.Address) *contract.Data {
+	now := time.Now().Unix()
+	return contract.NewData(
+		pm,
+		common.BytesToAddress(randomBytes(20)),
+		randomInt(128),
+		big.NewInt(now),
+		big.NewInt(now+int64(time.Hour.Seconds())),
+	)
+}
+
+func check(eth *ethclient.Client, chain *big.Int, ep common.Address, pm common.Address) (bool, error) {
+	op := randomOp()
+	data := randomData(pm)
+
+	v07, err := packedop.IsEntryPoint(eth, ep)
+	if err != nil {
+		return false, err
+	}
+
+	var onchain, offline [32]byte
+	if v07 {
+		op.Paymaster = pm
+		if onchain, err = contract.GetHashV07(eth, op.Pack(), data); err != nil {
+			return false, err
+		}
+		if offline, err = contract.ComputeHashV07(op.Pack(), chain, data); err != nil {
+			return false, err
+		}
+	} else {
+		// A random paymasterAndData also checks that it is excluded from the hash.
+		v06 := op.ToUserOperation()
+		v06.PaymasterAndData = randomBytes(150)
+		if onchain, err = contract.GetHash(eth, v06, data); err != nil {
+			return false, err
+		}
+		if offline, err = contract.ComputeHash(v06, chain, data); err != nil {
+			return false, err
+		}
+	}
+
+	return onchain == offline, nil
+}
+
+// checkChain checks every paymaster of a single chain and returns false if any of the hashes do not match.
+func checkChain(cv *config.ChainValues, iterations int) bool {
+	eth, err := ethclient.Dial(cv.EthClientUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer eth.Close()
+	chain, err := eth.ChainID(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ok := true
+	for ep, pms := range cv.EntryPointToPaymasters {
+		for _, pm := range pms {
+			for i := 0; i < iterations; i++ {
+				match, err := check(eth, chain, ep, pm)
+				if err != nil {
+					log.Fatal(err)
+				}
+				if !match {
+					ok = false
+					fmt.Printf(
+						"MISMATCH chain=%s entrypoint=%s paymaster=%s iteration=%d\n",
+						chain,
+						ep.Hex(),
+						pm.Hex(),
+						i,
+					)
+				}
+			}
+			fmt.Printf(
+				"checked chain=%s entrypoint=%s paymaster=%s iterations=%d\n",
+				chain,
+				ep.Hex(),
+				pm.Hex(),
+				iterations,
+			)
+		}
+	}
+	return ok
+}
+
+// Run checks ComputeHash against getHash on every configured paymaster of every chain with randomized inputs.
+// The process exits with a non-zero code if any of the hashes do not match.
+func Run(iterations int) {
+	conf := config.GetValues()
+
+	ok := true
+	for _, cv := range conf.Chains {
+		if !checkChain(cv, iterations) {
+			ok = false
+		}
+	}
+
+	if !ok {
+		os.Exit(1)
+	}
+	fmt.Println("ok")
+}
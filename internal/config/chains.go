@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stackup-wallet/stackup-paymaster/internal/yamlutil"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/estimator"
+)
+
+// SignerValues are the variables used to build the verifying signer of a chain.
+type SignerValues struct {
+	Type                 string         `yaml:"type"`
+	SigningKey           string         `yaml:"signingKey"`
+	KeystoreFile         string         `yaml:"keystoreFile"`
+	KeystorePassword     string         `yaml:"keystorePassword"`
+	KeystorePasswordFile string         `yaml:"keystorePasswordFile"`
+	URL                  string         `yaml:"url"`
+	Address              common.Address `yaml:"address"`
+	KeyName              string         `yaml:"keyName"`
+	Token                string         `yaml:"token"`
+	TokenFile            string         `yaml:"tokenFile"`
+
+	// AdditionalSigners are the keys of other verifiers to sign with during a rotation. These are only read
+	// from the primary signer of a chain.
+	AdditionalSigners []*SignerValues `yaml:"additionalSigners"`
+}
+
+// setDefaultType sets the type of the signer and any additional signers to local if not set.
+func (s *SignerValues) setDefaultType() {
+	if s.Type == "" {
+		s.Type = "local"
+	}
+	for _, a := range s.AdditionalSigners {
+		if a.Type == "" {
+			a.Type = "local"
+		}
+	}
+}
+
+// ChainValues are the variables for a single chain served by the paymaster. When a chains file is not set,
+// there is exactly one chain built from the global variables.
+//
+// Budgets are in wei of the chain's native token, so each chain tracks its own global, sender, and API key
+// budgets. GlobalBudget and SenderBudget default to the global variables and should be set per chain when
+// serving more than one.
+//
+// The preVerificationGas overhead of known rollups is detected from the chain ID. Other rollups can set
+// IsOpStackNetwork or IsArbitrumNetwork, and the buffer for the network can be replaced with
+// Gas.PreVerificationGasBuffer.
+type ChainValues struct {
+	EthClientUrl                     string                              `yaml:"ethClientUrl"`
+	EntryPointToPaymasters           map[common.Address][]common.Address `yaml:"entryPointToPaymasters"`
+	IsOpStackNetwork                 bool                                `yaml:"isOpStackNetwork"`
+	IsArbitrumNetwork                bool                                `yaml:"isArbitrumNetwork"`
+	FeeStrategy                      string                              `yaml:"feeStrategy"`
+	V07PaymasterVerificationGasLimit *big.Int                            `yaml:"v07PaymasterVerificationGasLimit"`
+	V07PaymasterPostOpGasLimit       *big.Int                            `yaml:"v07PaymasterPostOpGasLimit"`
+	Gas                              *estimator.Limits                   `yaml:"gas"`
+	GlobalBudget                     *big.Int                            `yaml:"globalBudget"`
+	SenderBudget                     *big.Int                            `yaml:"senderBudget"`
+	Signer                           *SignerValues                       `yaml:"signer"`
+}
+
+type chainsFile struct {
+	Chains []*ChainValues `yaml:"chains"`
+}
+
+// readChainsFile parses the chains in a YAML or JSON file.
+func readChainsFile(path string) []*ChainValues {
+	var f chainsFile
+	if err := yamlutil.ReadFile(path, &f); err != nil {
+		panic(fmt.Errorf("fatal error chains file: %w", err))
+	}
+	if len(f.Chains) == 0 {
+		panic(fmt.Sprintf("Fatal config error: no chains in %s", path))
+	}
+
+	for i, c := range f.Chains {
+		if c.EthClientUrl == "" {
+			panic(fmt.Sprintf("Fatal config error: chains[%d].ethClientUrl not set", i))
+		}
+		if len(c.EntryPointToPaymasters) == 0 {
+			panic(fmt.Sprintf("Fatal config error: chains[%d].entryPointToPaymasters not set", i))
+		}
+		if c.Signer != nil {
+			c.Signer.setDefaultType()
+		}
+	}
+	return f.Chains
+}
+
+type signersFile struct {
+	Signers []*SignerValues `yaml:"signers"`
+}
+
+// readSignersFile parses the additional signers in a YAML or JSON file.
+func readSignersFile(path string) []*SignerValues {
+	var f signersFile
+	if err := yamlutil.ReadFile(path, &f); err != nil {
+		panic(fmt.Errorf("fatal error signers file: %w", err))
+	}
+	for _, s := range f.Signers {
+		if s.Type == "" {
+			s.Type = "local"
+		}
+	}
+	return f.Signers
+}
+
+// needsGlobalSigner returns true if any of the chains will fall back to the signer from the global
+// variables.
+func needsGlobalSigner(chains []*ChainValues) bool {
+	if len(chains) == 0 {
+		return true
+	}
+	for _, c := range chains {
+		if c.Signer == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// setDefaults sets any unset fee strategy, gas limits, budgets, or signer from the global variables. Gas estimation
+// limits are merged field by field.
+func (c *ChainValues) setDefaults(defaults *ChainValues) {
+	if c.FeeStrategy == "" {
+		c.FeeStrategy = defaults.FeeStrategy
+	}
+	if c.V07PaymasterVerificationGasLimit == nil {
+		c.V07PaymasterVerificationGasLimit = defaults.V07PaymasterVerificationGasLimit
+	}
+	if c.V07PaymasterPostOpGasLimit == nil {
+		c.V07PaymasterPostOpGasLimit = defaults.V07PaymasterPostOpGasLimit
+	}
+	if c.GlobalBudget == nil {
+		c.GlobalBudget = defaults.GlobalBudget
+	}
+	if c.SenderBudget == nil {
+		c.SenderBudget = defaults.SenderBudget
+	}
+	if c.Signer == nil {
+		c.Signer = defaults.Signer
+	}
+	c.Gas = defaults.Gas.Merge(c.Gas)
+}
@@ -0,0 +1,33 @@
+package config
+
+import "github.com/spf13/viper"
+
+// OwnerValues are the variables for admin commands that send transactions as the paymaster owner.
+type OwnerValues struct {
+	EthClientUrl string
+	OwnerKey     string
+}
+
+func GetOwnerValues() *OwnerValues {
+	// Read in from .env file if available
+	readEnvFile()
+
+	// Read in from environment variables
+	_ = viper.BindEnv("erc4337_paymaster_eth_client_url")
+	_ = viper.BindEnv("erc4337_paymaster_owner_key")
+
+	// Validate required variables
+	if variableNotSetOrIsNil("erc4337_paymaster_eth_client_url") {
+		panic("Fatal config error: erc4337_paymaster_eth_client_url not set")
+	}
+
+	if variableNotSetOrIsNil("erc4337_paymaster_owner_key") {
+		panic("Fatal config error: erc4337_paymaster_owner_key not set")
+	}
+
+	// Return values
+	return &OwnerValues{
+		EthClientUrl: viper.GetString("erc4337_paymaster_eth_client_url"),
+		OwnerKey:     viper.GetString("erc4337_paymaster_owner_key"),
+	}
+}
@@ -2,11 +2,14 @@ package config
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/estimator"
 )
 
 type Values struct {
@@ -16,6 +19,89 @@ type Values struct {
 	EntryPointToPaymasters map[common.Address][]common.Address
 	EthClientUrl           string
 
+	// Keystore variables.
+	KeystoreFile         string
+	KeystorePassword     string
+	KeystorePasswordFile string
+
+	// Remote signer variables.
+	SignerType      string
+	SignerURL       string
+	SignerAddress   common.Address
+	SignerKeyName   string
+	SignerToken     string
+	SignerTokenFile string
+
+	// Multi-chain variables. Chains always has at least one entry. If ChainsFile is not set it will only
+	// contain the chain from the global variables.
+	ChainsFile string
+	Chains     []*ChainValues
+
+	// Key rotation variables.
+	AdditionalSignersFile   string
+	VerifierRefreshInterval time.Duration
+
+	// Validity window variables.
+	ValidFor           time.Duration
+	MaxValidFor        time.Duration
+	MaxValidAfterDelay time.Duration
+
+	// EntryPoint v0.7 variables.
+	V07PaymasterVerificationGasLimit *big.Int
+	V07PaymasterPostOpGasLimit       *big.Int
+
+	// Gas estimation variables.
+	GasLimits *estimator.Limits
+
+	// Validation variables.
+	SimulateValidation bool
+
+	// ERC-20 token sponsorship variables.
+	ERC20TokenToExchangeRate map[common.Address]*big.Int
+	ERC20TokenToOracleSource map[common.Address]string
+	OracleMarkupBps          int64
+	OracleCacheTTL           time.Duration
+	OracleMaxStaleness       time.Duration
+	OracleTWAPWindow         time.Duration
+
+	// Sponsorship policy variables.
+	PoliciesFile string
+
+	// API key variables. A reload interval of 0 turns off timed reloads so the file is only reloaded on
+	// SIGHUP.
+	APIKeysFile           string
+	APIKeysReloadInterval time.Duration
+
+	// Access list variables. A reload interval of 0 turns off timed reloads so the source is only reloaded
+	// on SIGHUP.
+	AccessListSource         string
+	AccessListReloadInterval time.Duration
+
+	// Budget variables. The amounts are the defaults for each chain, see ChainValues.
+	DataDirectory      string
+	GlobalBudget       *big.Int
+	GlobalBudgetPeriod string
+	SenderBudget       *big.Int
+	SenderBudgetPeriod string
+
+	// Watcher variables.
+	WatcherInterval time.Duration
+
+	// Paymaster selection variables.
+	PaymasterSelectionStrategy string
+
+	// Fee suggestion variables.
+	FeeStrategy          string
+	FeeHistoryBlocks     uint64
+	FeeHistoryPercentile float64
+	BaseFeeBufferPercent int64
+
+	// Deposit monitor variables.
+	DepositMonitorInterval time.Duration
+	MinDeposit             *big.Int
+	MinStake               *big.Int
+	EnforceDeposit         bool
+
 	// Observability variables.
 	OTELServiceName      string
 	OTELCollectorHeaders map[string]string
@@ -23,7 +109,8 @@ type Values struct {
 	OTELInsecureMode     bool
 
 	// Rollup related variables.
-	IsOpStackNetwork bool
+	IsOpStackNetwork  bool
+	IsArbitrumNetwork bool
 
 	// Undocumented variables.
 	GinMode string
@@ -63,19 +150,66 @@ func envKeyValAddressToAddressSlice(s string) map[common.Address][]common.Addres
 	return out
 }
 
+func envKeyValAddressToBigInt(s string) map[common.Address]*big.Int {
+	out := map[common.Address]*big.Int{}
+	if s == "" {
+		return out
+	}
+	for _, pair := range strings.Split(s, "&") {
+		kv := strings.Split(pair, "=")
+		if len(kv) != 2 {
+			break
+		}
+		val, ok := big.NewInt(0).SetString(strings.TrimSpace(kv[1]), 0)
+		if !ok {
+			panic(fmt.Sprintf("Fatal config error: %s is not a valid integer", kv[1]))
+		}
+		out[common.HexToAddress(strings.TrimSpace(kv[0]))] = val
+	}
+	return out
+}
+
+func envKeyValAddressToString(s string) map[common.Address]string {
+	out := map[common.Address]string{}
+	if s == "" {
+		return out
+	}
+	for _, pair := range strings.Split(s, "&") {
+		kv := strings.Split(pair, "=")
+		if len(kv) != 2 {
+			break
+		}
+		out[common.HexToAddress(strings.TrimSpace(kv[0]))] = strings.TrimSpace(kv[1])
+	}
+	return out
+}
+
+func envStringToBigInt(env string) *big.Int {
+	if variableNotSetOrIsNil(env) {
+		return nil
+	}
+
+	val, ok := big.NewInt(0).SetString(viper.GetString(env), 0)
+	if !ok {
+		panic(fmt.Sprintf("Fatal config error: %s is not a valid integer", env))
+	}
+	return val
+}
+
+func envStringToInt64Ptr(env string) *int64 {
+	if variableNotSetOrIsNil(env) {
+		return nil
+	}
+
+	val := viper.GetInt64(env)
+	return &val
+}
+
 func variableNotSetOrIsNil(env string) bool {
 	return !viper.IsSet(env) || viper.GetString(env) == ""
 }
 
-func GetValues() *Values {
-	// Default variables
-	viper.SetDefault("erc4337_paymaster_port", 43371)
-	viper.SetDefault("erc4337_paymaster_default_entrypoint", "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
-	viper.SetDefault("erc4337_paymaster_otel_insecure_mode", false)
-	viper.SetDefault("erc4337_paymaster_is_op_stack_network", false)
-	viper.SetDefault("erc4337_paymaster_gin_mode", gin.ReleaseMode)
-
-	// Read in from .env file if available
+func readEnvFile() {
 	viper.SetConfigName(".env")
 	viper.SetConfigType("env")
 	viper.AddConfigPath(".")
@@ -87,30 +221,152 @@ func GetValues() *Values {
 			panic(fmt.Errorf("fatal error config file: %w", err))
 		}
 	}
+}
+
+func GetValues() *Values {
+	// Default variables
+	viper.SetDefault("erc4337_paymaster_port", 43371)
+	viper.SetDefault("erc4337_paymaster_default_entrypoint", "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	viper.SetDefault("erc4337_paymaster_signer_type", "local")
+	viper.SetDefault("erc4337_paymaster_verifier_refresh_interval", time.Minute)
+	viper.SetDefault("erc4337_paymaster_valid_for", time.Hour)
+	viper.SetDefault("erc4337_paymaster_max_valid_for", 24*time.Hour)
+	viper.SetDefault("erc4337_paymaster_max_valid_after_delay", 24*time.Hour)
+	viper.SetDefault("erc4337_paymaster_v07_paymaster_verification_gas_limit", 60000)
+	viper.SetDefault("erc4337_paymaster_v07_paymaster_post_op_gas_limit", 60000)
+	viper.SetDefault("erc4337_paymaster_max_total_gas", 18000000)
+	viper.SetDefault("erc4337_paymaster_simulate_validation", true)
+	viper.SetDefault("erc4337_paymaster_oracle_markup_bps", 0)
+	viper.SetDefault("erc4337_paymaster_oracle_cache_ttl", 30*time.Second)
+	viper.SetDefault("erc4337_paymaster_oracle_max_staleness", 24*time.Hour)
+	viper.SetDefault("erc4337_paymaster_oracle_twap_window", 30*time.Minute)
+	viper.SetDefault("erc4337_paymaster_api_keys_reload_interval", 30*time.Second)
+	viper.SetDefault("erc4337_paymaster_access_list_reload_interval", 30*time.Second)
+	viper.SetDefault("erc4337_paymaster_global_budget_period", "day")
+	viper.SetDefault("erc4337_paymaster_sender_budget_period", "day")
+	viper.SetDefault("erc4337_paymaster_watcher_interval", 15*time.Second)
+	viper.SetDefault("erc4337_paymaster_deposit_monitor_interval", time.Minute)
+	viper.SetDefault("erc4337_paymaster_paymaster_selection_strategy", "first")
+	viper.SetDefault("erc4337_paymaster_enforce_deposit", false)
+	viper.SetDefault("erc4337_paymaster_fee_history_blocks", 10)
+	viper.SetDefault("erc4337_paymaster_fee_history_percentile", 50)
+	viper.SetDefault("erc4337_paymaster_base_fee_buffer_percent", 100)
+	viper.SetDefault("erc4337_paymaster_otel_insecure_mode", false)
+	viper.SetDefault("erc4337_paymaster_is_op_stack_network", false)
+	viper.SetDefault("erc4337_paymaster_is_arbitrum_network", false)
+	viper.SetDefault("erc4337_paymaster_gin_mode", gin.ReleaseMode)
+
+	// Read in from .env file if available
+	readEnvFile()
 
 	// Read in from environment variables
 	_ = viper.BindEnv("erc4337_paymaster_port")
 	_ = viper.BindEnv("erc4337_paymaster_default_entrypoint")
 	_ = viper.BindEnv("erc4337_paymaster_signing_key")
+	_ = viper.BindEnv("erc4337_paymaster_keystore_file")
+	_ = viper.BindEnv("erc4337_paymaster_keystore_password")
+	_ = viper.BindEnv("erc4337_paymaster_keystore_password_file")
+	_ = viper.BindEnv("erc4337_paymaster_signer_type")
+	_ = viper.BindEnv("erc4337_paymaster_signer_url")
+	_ = viper.BindEnv("erc4337_paymaster_signer_address")
+	_ = viper.BindEnv("erc4337_paymaster_signer_key_name")
+	_ = viper.BindEnv("erc4337_paymaster_signer_token")
+	_ = viper.BindEnv("erc4337_paymaster_signer_token_file")
+	_ = viper.BindEnv("erc4337_paymaster_additional_signers_file")
+	_ = viper.BindEnv("erc4337_paymaster_verifier_refresh_interval")
 	_ = viper.BindEnv("erc4337_paymaster_entrypoint_to_paymasters")
+	_ = viper.BindEnv("erc4337_paymaster_chains_file")
 	_ = viper.BindEnv("erc4337_paymaster_eth_client_url")
+	_ = viper.BindEnv("erc4337_paymaster_valid_for")
+	_ = viper.BindEnv("erc4337_paymaster_max_valid_for")
+	_ = viper.BindEnv("erc4337_paymaster_max_valid_after_delay")
+	_ = viper.BindEnv("erc4337_paymaster_v07_paymaster_verification_gas_limit")
+	_ = viper.BindEnv("erc4337_paymaster_v07_paymaster_post_op_gas_limit")
+	_ = viper.BindEnv("erc4337_paymaster_verification_gas_buffer")
+	_ = viper.BindEnv("erc4337_paymaster_call_gas_buffer")
+	_ = viper.BindEnv("erc4337_paymaster_pre_verification_gas_buffer")
+	_ = viper.BindEnv("erc4337_paymaster_min_verification_gas_limit")
+	_ = viper.BindEnv("erc4337_paymaster_max_verification_gas_limit")
+	_ = viper.BindEnv("erc4337_paymaster_min_call_gas_limit")
+	_ = viper.BindEnv("erc4337_paymaster_max_call_gas_limit")
+	_ = viper.BindEnv("erc4337_paymaster_min_pre_verification_gas")
+	_ = viper.BindEnv("erc4337_paymaster_max_pre_verification_gas")
+	_ = viper.BindEnv("erc4337_paymaster_max_total_gas")
+	_ = viper.BindEnv("erc4337_paymaster_simulate_validation")
+	_ = viper.BindEnv("erc4337_paymaster_erc20_token_to_exchange_rate")
+	_ = viper.BindEnv("erc4337_paymaster_erc20_token_to_oracle_source")
+	_ = viper.BindEnv("erc4337_paymaster_oracle_markup_bps")
+	_ = viper.BindEnv("erc4337_paymaster_oracle_cache_ttl")
+	_ = viper.BindEnv("erc4337_paymaster_oracle_max_staleness")
+	_ = viper.BindEnv("erc4337_paymaster_oracle_twap_window")
+	_ = viper.BindEnv("erc4337_paymaster_policies_file")
+	_ = viper.BindEnv("erc4337_paymaster_api_keys_file")
+	_ = viper.BindEnv("erc4337_paymaster_api_keys_reload_interval")
+	_ = viper.BindEnv("erc4337_paymaster_access_list_source")
+	_ = viper.BindEnv("erc4337_paymaster_access_list_reload_interval")
+	_ = viper.BindEnv("erc4337_paymaster_data_directory")
+	_ = viper.BindEnv("erc4337_paymaster_global_budget")
+	_ = viper.BindEnv("erc4337_paymaster_global_budget_period")
+	_ = viper.BindEnv("erc4337_paymaster_sender_budget")
+	_ = viper.BindEnv("erc4337_paymaster_sender_budget_period")
+	_ = viper.BindEnv("erc4337_paymaster_watcher_interval")
+	_ = viper.BindEnv("erc4337_paymaster_deposit_monitor_interval")
+	_ = viper.BindEnv("erc4337_paymaster_paymaster_selection_strategy")
+	_ = viper.BindEnv("erc4337_paymaster_fee_strategy")
+	_ = viper.BindEnv("erc4337_paymaster_fee_history_blocks")
+	_ = viper.BindEnv("erc4337_paymaster_fee_history_percentile")
+	_ = viper.BindEnv("erc4337_paymaster_base_fee_buffer_percent")
+	_ = viper.BindEnv("erc4337_paymaster_min_deposit")
+	_ = viper.BindEnv("erc4337_paymaster_min_stake")
+	_ = viper.BindEnv("erc4337_paymaster_enforce_deposit")
 	_ = viper.BindEnv("erc4337_paymaster_otel_service_name")
 	_ = viper.BindEnv("erc4337_paymaster_otel_collector_headers")
 	_ = viper.BindEnv("erc4337_paymaster_otel_collector_url")
 	_ = viper.BindEnv("erc4337_paymaster_otel_insecure_mode")
 	_ = viper.BindEnv("erc4337_paymaster_is_op_stack_network")
+	_ = viper.BindEnv("erc4337_paymaster_is_arbitrum_network")
 	_ = viper.BindEnv("erc4337_paymaster_gin_mode")
 
 	// Validate required variables
-	if variableNotSetOrIsNil("erc4337_paymaster_signing_key") {
-		panic("Fatal config error: erc4337_paymaster_signing_key not set")
+	chainsFile := viper.GetString("erc4337_paymaster_chains_file")
+	var chains []*ChainValues
+	if chainsFile != "" {
+		chains = readChainsFile(chainsFile)
 	}
 
-	if variableNotSetOrIsNil("erc4337_paymaster_entrypoint_to_paymasters") {
+	switch signerType := viper.GetString("erc4337_paymaster_signer_type"); {
+	case !needsGlobalSigner(chains):
+		// Every chain has its own signer.
+	case signerType == "local":
+		if variableNotSetOrIsNil("erc4337_paymaster_signing_key") &&
+			variableNotSetOrIsNil("erc4337_paymaster_keystore_file") {
+			panic("Fatal config error: erc4337_paymaster_signing_key or erc4337_paymaster_keystore_file not set")
+		}
+		if !variableNotSetOrIsNil("erc4337_paymaster_keystore_file") &&
+			variableNotSetOrIsNil("erc4337_paymaster_keystore_password") &&
+			variableNotSetOrIsNil("erc4337_paymaster_keystore_password_file") {
+			panic("Fatal config error: erc4337_paymaster_keystore_file is set without a password")
+		}
+	case signerType == "web3signer":
+		if variableNotSetOrIsNil("erc4337_paymaster_signer_url") {
+			panic("Fatal config error: erc4337_paymaster_signer_url not set")
+		}
+		if variableNotSetOrIsNil("erc4337_paymaster_signer_address") {
+			panic("Fatal config error: erc4337_paymaster_signer_address not set")
+		}
+	case signerType == "kms":
+		if variableNotSetOrIsNil("erc4337_paymaster_signer_key_name") {
+			panic("Fatal config error: erc4337_paymaster_signer_key_name not set")
+		}
+	default:
+		panic("Fatal config error: erc4337_paymaster_signer_type must be one of local, web3signer, or kms")
+	}
+
+	if chainsFile == "" && variableNotSetOrIsNil("erc4337_paymaster_entrypoint_to_paymasters") {
 		panic("Fatal config error: erc4337_paymaster_entrypoint_to_paymasters not set")
 	}
 
-	if variableNotSetOrIsNil("erc4337_paymaster_eth_client_url") {
+	if chainsFile == "" && variableNotSetOrIsNil("erc4337_paymaster_eth_client_url") {
 		panic("Fatal config error: erc4337_paymaster_eth_client_url not set")
 	}
 
@@ -124,27 +380,176 @@ func GetValues() *Values {
 	port := viper.GetInt("erc4337_paymaster_port")
 	defaultEntryPoint := common.HexToAddress(viper.GetString("erc4337_paymaster_default_entrypoint"))
 	signingKey := viper.GetString("erc4337_paymaster_signing_key")
+	keystoreFile := viper.GetString("erc4337_paymaster_keystore_file")
+	keystorePassword := viper.GetString("erc4337_paymaster_keystore_password")
+	keystorePasswordFile := viper.GetString("erc4337_paymaster_keystore_password_file")
+	signerType := viper.GetString("erc4337_paymaster_signer_type")
+	signerURL := viper.GetString("erc4337_paymaster_signer_url")
+	signerAddress := common.HexToAddress(viper.GetString("erc4337_paymaster_signer_address"))
+	signerKeyName := viper.GetString("erc4337_paymaster_signer_key_name")
+	signerToken := viper.GetString("erc4337_paymaster_signer_token")
+	signerTokenFile := viper.GetString("erc4337_paymaster_signer_token_file")
+	additionalSignersFile := viper.GetString("erc4337_paymaster_additional_signers_file")
+	var additionalSigners []*SignerValues
+	if additionalSignersFile != "" {
+		additionalSigners = readSignersFile(additionalSignersFile)
+	}
+	verifierRefreshInterval := viper.GetDuration("erc4337_paymaster_verifier_refresh_interval")
 	entryPointToPaymasters := envKeyValAddressToAddressSlice(
 		viper.GetString("erc4337_paymaster_entrypoint_to_paymasters"),
 	)
 	ethClientUrl := viper.GetString("erc4337_paymaster_eth_client_url")
+	validFor := viper.GetDuration("erc4337_paymaster_valid_for")
+	maxValidFor := viper.GetDuration("erc4337_paymaster_max_valid_for")
+	maxValidAfterDelay := viper.GetDuration("erc4337_paymaster_max_valid_after_delay")
+	v07PaymasterVerificationGasLimit := big.NewInt(
+		viper.GetInt64("erc4337_paymaster_v07_paymaster_verification_gas_limit"),
+	)
+	v07PaymasterPostOpGasLimit := big.NewInt(viper.GetInt64("erc4337_paymaster_v07_paymaster_post_op_gas_limit"))
+	gasLimits := &estimator.Limits{
+		VerificationGasBuffer:    envStringToInt64Ptr("erc4337_paymaster_verification_gas_buffer"),
+		CallGasBuffer:            envStringToInt64Ptr("erc4337_paymaster_call_gas_buffer"),
+		PreVerificationGasBuffer: envStringToInt64Ptr("erc4337_paymaster_pre_verification_gas_buffer"),
+		MinVerificationGasLimit:  envStringToBigInt("erc4337_paymaster_min_verification_gas_limit"),
+		MaxVerificationGasLimit:  envStringToBigInt("erc4337_paymaster_max_verification_gas_limit"),
+		MinCallGasLimit:          envStringToBigInt("erc4337_paymaster_min_call_gas_limit"),
+		MaxCallGasLimit:          envStringToBigInt("erc4337_paymaster_max_call_gas_limit"),
+		MinPreVerificationGas:    envStringToBigInt("erc4337_paymaster_min_pre_verification_gas"),
+		MaxPreVerificationGas:    envStringToBigInt("erc4337_paymaster_max_pre_verification_gas"),
+		MaxTotalGas:              envStringToBigInt("erc4337_paymaster_max_total_gas"),
+	}
+	simulateValidation := viper.GetBool("erc4337_paymaster_simulate_validation")
+	erc20TokenToExchangeRate := envKeyValAddressToBigInt(
+		viper.GetString("erc4337_paymaster_erc20_token_to_exchange_rate"),
+	)
+	erc20TokenToOracleSource := envKeyValAddressToString(
+		viper.GetString("erc4337_paymaster_erc20_token_to_oracle_source"),
+	)
+	oracleMarkupBps := viper.GetInt64("erc4337_paymaster_oracle_markup_bps")
+	oracleCacheTTL := viper.GetDuration("erc4337_paymaster_oracle_cache_ttl")
+	oracleMaxStaleness := viper.GetDuration("erc4337_paymaster_oracle_max_staleness")
+	oracleTWAPWindow := viper.GetDuration("erc4337_paymaster_oracle_twap_window")
+	policiesFile := viper.GetString("erc4337_paymaster_policies_file")
+	apiKeysFile := viper.GetString("erc4337_paymaster_api_keys_file")
+	apiKeysReloadInterval := viper.GetDuration("erc4337_paymaster_api_keys_reload_interval")
+	accessListSource := viper.GetString("erc4337_paymaster_access_list_source")
+	accessListReloadInterval := viper.GetDuration("erc4337_paymaster_access_list_reload_interval")
+	dataDirectory := viper.GetString("erc4337_paymaster_data_directory")
+	globalBudget := envStringToBigInt("erc4337_paymaster_global_budget")
+	globalBudgetPeriod := viper.GetString("erc4337_paymaster_global_budget_period")
+	senderBudget := envStringToBigInt("erc4337_paymaster_sender_budget")
+	senderBudgetPeriod := viper.GetString("erc4337_paymaster_sender_budget_period")
+	watcherInterval := viper.GetDuration("erc4337_paymaster_watcher_interval")
+	depositMonitorInterval := viper.GetDuration("erc4337_paymaster_deposit_monitor_interval")
+	paymasterSelectionStrategy := viper.GetString("erc4337_paymaster_paymaster_selection_strategy")
+	feeStrategy := viper.GetString("erc4337_paymaster_fee_strategy")
+	feeHistoryBlocks := viper.GetUint64("erc4337_paymaster_fee_history_blocks")
+	feeHistoryPercentile := viper.GetFloat64("erc4337_paymaster_fee_history_percentile")
+	baseFeeBufferPercent := viper.GetInt64("erc4337_paymaster_base_fee_buffer_percent")
+	minDeposit := envStringToBigInt("erc4337_paymaster_min_deposit")
+	minStake := envStringToBigInt("erc4337_paymaster_min_stake")
+	enforceDeposit := viper.GetBool("erc4337_paymaster_enforce_deposit")
 	otelServiceName := viper.GetString("erc4337_paymaster_otel_service_name")
 	otelCollectorHeader := envKeyValStringToMap(viper.GetString("erc4337_paymaster_otel_collector_headers"))
 	otelCollectorUrl := viper.GetString("erc4337_paymaster_otel_collector_url")
 	otelInsecureMode := viper.GetBool("erc4337_paymaster_otel_insecure_mode")
 	isOpStackNetwork := viper.GetBool("erc4337_paymaster_is_op_stack_network")
+	isArbitrumNetwork := viper.GetBool("erc4337_paymaster_is_arbitrum_network")
 	ginMode := viper.GetString("erc4337_paymaster_gin_mode")
+
+	globalChain := &ChainValues{
+		EthClientUrl:                     ethClientUrl,
+		EntryPointToPaymasters:           entryPointToPaymasters,
+		IsOpStackNetwork:                 isOpStackNetwork,
+		IsArbitrumNetwork:                isArbitrumNetwork,
+		FeeStrategy:                      feeStrategy,
+		V07PaymasterVerificationGasLimit: v07PaymasterVerificationGasLimit,
+		V07PaymasterPostOpGasLimit:       v07PaymasterPostOpGasLimit,
+		Gas:                              gasLimits,
+		GlobalBudget:                     globalBudget,
+		SenderBudget:                     senderBudget,
+		Signer: &SignerValues{
+			Type:                 signerType,
+			SigningKey:           signingKey,
+			KeystoreFile:         keystoreFile,
+			KeystorePassword:     keystorePassword,
+			KeystorePasswordFile: keystorePasswordFile,
+			URL:                  signerURL,
+			Address:              signerAddress,
+			KeyName:              signerKeyName,
+			Token:                signerToken,
+			TokenFile:            signerTokenFile,
+			AdditionalSigners:    additionalSigners,
+		},
+	}
+	if chainsFile == "" {
+		chains = []*ChainValues{globalChain}
+	}
+	for i, c := range chains {
+		c.setDefaults(globalChain)
+		if err := c.Gas.Validate(); err != nil {
+			panic(fmt.Sprintf("Fatal config error: chains[%d]: %s", i, err))
+		}
+	}
+
 	return &Values{
-		Port:                   port,
-		DefaultEntryPoint:      defaultEntryPoint,
-		SigningKey:             signingKey,
-		EntryPointToPaymasters: entryPointToPaymasters,
-		EthClientUrl:           ethClientUrl,
-		OTELServiceName:        otelServiceName,
-		OTELCollectorHeaders:   otelCollectorHeader,
-		OTELCollectorUrl:       otelCollectorUrl,
-		OTELInsecureMode:       otelInsecureMode,
-		IsOpStackNetwork:       isOpStackNetwork,
-		GinMode:                ginMode,
+		Port:                             port,
+		DefaultEntryPoint:                defaultEntryPoint,
+		SigningKey:                       signingKey,
+		KeystoreFile:                     keystoreFile,
+		KeystorePassword:                 keystorePassword,
+		KeystorePasswordFile:             keystorePasswordFile,
+		SignerType:                       signerType,
+		SignerURL:                        signerURL,
+		SignerAddress:                    signerAddress,
+		SignerKeyName:                    signerKeyName,
+		SignerToken:                      signerToken,
+		SignerTokenFile:                  signerTokenFile,
+		AdditionalSignersFile:            additionalSignersFile,
+		VerifierRefreshInterval:          verifierRefreshInterval,
+		EntryPointToPaymasters:           entryPointToPaymasters,
+		EthClientUrl:                     ethClientUrl,
+		ChainsFile:                       chainsFile,
+		Chains:                           chains,
+		ValidFor:                         validFor,
+		MaxValidFor:                      maxValidFor,
+		MaxValidAfterDelay:               maxValidAfterDelay,
+		V07PaymasterVerificationGasLimit: v07PaymasterVerificationGasLimit,
+		V07PaymasterPostOpGasLimit:       v07PaymasterPostOpGasLimit,
+		GasLimits:                        gasLimits,
+		SimulateValidation:               simulateValidation,
+		ERC20TokenToExchangeRate:         erc20TokenToExchangeRate,
+		ERC20TokenToOracleSource:         erc20TokenToOracleSource,
+		OracleMarkupBps:                  oracleMarkupBps,
+		OracleCacheTTL:                   oracleCacheTTL,
+		OracleMaxStaleness:               oracleMaxStaleness,
+		OracleTWAPWindow:                 oracleTWAPWindow,
+		PoliciesFile:                     policiesFile,
+		APIKeysFile:                      apiKeysFile,
+		APIKeysReloadInterval:            apiKeysReloadInterval,
+		AccessListSource:                 accessListSource,
+		AccessListReloadInterval:         accessListReloadInterval,
+		DataDirectory:                    dataDirectory,
+		GlobalBudget:                     globalBudget,
+		GlobalBudgetPeriod:               globalBudgetPeriod,
+		SenderBudget:                     senderBudget,
+		SenderBudgetPeriod:               senderBudgetPeriod,
+		WatcherInterval:                  watcherInterval,
+		DepositMonitorInterval:           depositMonitorInterval,
+		PaymasterSelectionStrategy:       paymasterSelectionStrategy,
+		FeeStrategy:                      feeStrategy,
+		FeeHistoryBlocks:                 feeHistoryBlocks,
+		FeeHistoryPercentile:             feeHistoryPercentile,
+		BaseFeeBufferPercent:             baseFeeBufferPercent,
+		MinDeposit:                       minDeposit,
+		MinStake:                         minStake,
+		EnforceDeposit:                   enforceDeposit,
+		OTELServiceName:                  otelServiceName,
+		OTELCollectorHeaders:             otelCollectorHeader,
+		OTELCollectorUrl:                 otelCollectorUrl,
+		OTELInsecureMode:                 otelInsecureMode,
+		IsOpStackNetwork:                 isOpStackNetwork,
+		IsArbitrumNetwork:                isArbitrumNetwork,
+		GinMode:                          ginMode,
 	}
 }
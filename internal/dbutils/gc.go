@@ -0,0 +1,23 @@
+package dbutils
+
+import (
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// RunGarbageCollection periodically cleans up the value log of the DB in a separate goroutine.
+func RunGarbageCollection(db *badger.DB) {
+	go func(db *badger.DB) {
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+		again:
+			err := db.RunValueLogGC(0.7)
+			if err == nil {
+				goto again
+			}
+		}
+	}(db)
+}
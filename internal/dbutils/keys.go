@@ -0,0 +1,10 @@
+package dbutils
+
+import "strings"
+
+const separator = ":"
+
+// JoinValues joins values into a single database key.
+func JoinValues(values ...string) string {
+	return strings.Join(values, separator)
+}
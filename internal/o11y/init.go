@@ -12,6 +12,7 @@ import (
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/grpc/credentials"
 
@@ -26,20 +27,26 @@ type Opts struct {
 	CollectorUrl    string
 	InsecureMode    bool
 
-	// paymaster specific attributes
+	// paymaster specific attributes. These are omitted if the paymaster is serving more than one chain.
 	ChainID       *big.Int
 	SignerAddress common.Address
 }
 
 func initResources(opts *Opts) *resource.Resource {
-	resources, err := resource.New(
-		context.Background(),
-		resource.WithAttributes(
-			attribute.String("service.name", opts.ServiceName),
-			attribute.String("library.language", "go"),
+	attrs := []attribute.KeyValue{
+		attribute.String("service.name", opts.ServiceName),
+		attribute.String("library.language", "go"),
+	}
+	if opts.ChainID != nil {
+		attrs = append(
+			attrs,
 			attribute.String("paymaster.signer_address", opts.SignerAddress.Hex()),
 			attribute.Int64("paymaster.chain_id", opts.ChainID.Int64()),
-		),
+		)
+	}
+	resources, err := resource.New(
+		context.Background(),
+		resource.WithAttributes(attrs...),
 	)
 	if err != nil {
 		log.Fatal(err)
@@ -112,3 +119,9 @@ func InitMetrics(opts *Opts) func() {
 		_ = exporter.Shutdown(context.Background())
 	}
 }
+
+// Meter returns a named meter for registering instruments. Instruments are exported through the provider set
+// by InitMetrics and are a no-op if metrics are not enabled.
+func Meter(name string) metric.Meter {
+	return otel.Meter(name)
+}
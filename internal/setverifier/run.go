@@ -0,0 +1,73 @@
+// Package setverifier updates the verifier of a paymaster contract.
+package setverifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stackup-wallet/stackup-paymaster/internal/config"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+)
+
+// Run submits a setVerifier transaction to the paymaster as the owner and waits for it to be mined. Running
+// servers will start signing with the new verifier key on their next refresh, as long as they hold it.
+func Run(pm common.Address, verifier common.Address) {
+	conf := config.GetOwnerValues()
+
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(conf.OwnerKey, "0x"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	eth, err := ethclient.Dial(conf.EthClientUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	chain, err := eth.ChainID(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	c, err := contract.NewContract(pm, eth)
+	if err != nil {
+		log.Fatal(err)
+	}
+	prev, err := c.Verifier(&bind.CallOpts{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if prev == verifier {
+		fmt.Printf("paymaster %s already has verifier %s\n", pm.Hex(), verifier.Hex())
+		return
+	}
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key, chain)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tx, err := c.SetVerifier(opts, verifier)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("submitted setVerifier tx %s\n", tx.Hash().Hex())
+
+	receipt, err := bind.WaitMined(context.Background(), eth, tx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if receipt.Status != 1 {
+		log.Fatalf("setVerifier tx %s reverted", tx.Hash().Hex())
+	}
+	fmt.Printf(
+		"paymaster %s verifier updated from %s to %s in block %s\n",
+		pm.Hex(),
+		prev.Hex(),
+		verifier.Hex(),
+		receipt.BlockNumber,
+	)
+}
@@ -0,0 +1,332 @@
+package start
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/go-logr/logr"
+	"github.com/stackup-wallet/stackup-bundler/pkg/gas"
+	"github.com/stackup-wallet/stackup-paymaster/internal/config"
+	"github.com/stackup-wallet/stackup-paymaster/internal/dbutils"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/accesslist"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/budget"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/client"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/deposit"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/estimator"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/fees"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/keyring"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/oracle"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/selector"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/signer"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/validation"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/watcher"
+)
+
+// chain is a single network served by the paymaster.
+type chain struct {
+	conf    *config.ChainValues
+	rpc     *rpc.Client
+	eth     *ethclient.Client
+	id      *big.Int
+	signers []signer.Signer
+	db      *badger.DB
+
+	// v07 is the set of configured entry points that are EntryPoint v0.7.
+	v07 map[common.Address]bool
+}
+
+// newSigner builds a signer from its config.
+func newSigner(conf *config.SignerValues) (signer.Signer, error) {
+	keystorePassword := conf.KeystorePassword
+	if conf.KeystorePasswordFile != "" {
+		b, err := os.ReadFile(conf.KeystorePasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		keystorePassword = strings.TrimRight(string(b), "\r\n")
+	}
+	return signer.New(&signer.Opts{
+		Type:             conf.Type,
+		PrivateKey:       conf.SigningKey,
+		KeystoreFile:     conf.KeystoreFile,
+		KeystorePassword: keystorePassword,
+		URL:              conf.URL,
+		Address:          conf.Address,
+		KeyName:          conf.KeyName,
+		Token:            conf.Token,
+		TokenFile:        conf.TokenFile,
+	})
+}
+
+// newSigners builds the primary signer of a chain followed by any additional signers used during a key
+// rotation.
+func newSigners(conf *config.SignerValues) ([]signer.Signer, error) {
+	primary, err := newSigner(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	signers := []signer.Signer{primary}
+	for _, a := range conf.AdditionalSigners {
+		s, err := newSigner(a)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, s)
+	}
+	return signers, nil
+}
+
+// dialChain connects to the RPC of a chain, sets up its signers, and detects the version of each entry
+// point.
+func dialChain(conf *config.ChainValues) (*chain, error) {
+	signers, err := newSigners(conf.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	rpc, err := rpc.Dial(conf.EthClientUrl)
+	if err != nil {
+		return nil, err
+	}
+	eth := ethclient.NewClient(rpc)
+
+	id, err := eth.ChainID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	v07 := make(map[common.Address]bool)
+	for ep := range conf.EntryPointToPaymasters {
+		if v07[ep], err = packedop.IsEntryPoint(eth, ep); err != nil {
+			return nil, err
+		}
+	}
+
+	return &chain{
+		conf:    conf,
+		rpc:     rpc,
+		eth:     eth,
+		id:      id,
+		signers: signers,
+		v07:     v07,
+	}, nil
+}
+
+// openDB opens the database for the chain. If there is more than one chain, each will be in a separate
+// subdirectory named by chain ID.
+func (ch *chain) openDB(conf *config.Values) error {
+	dir := conf.DataDirectory
+	if dir != "" && conf.ChainsFile != "" {
+		dir = filepath.Join(dir, ch.id.String())
+	}
+
+	dbOpts := badger.DefaultOptions(dir)
+	if dir == "" {
+		dbOpts = dbOpts.WithInMemory(true)
+	}
+	db, err := badger.Open(dbOpts.WithLoggingLevel(badger.WARNING))
+	if err != nil {
+		return err
+	}
+	dbutils.RunGarbageCollection(db)
+
+	ch.db = db
+	return nil
+}
+
+// isArbitrum returns true if the chain is a known Arbitrum network or is set as one in its config.
+func (ch *chain) isArbitrum() bool {
+	return ch.conf.IsArbitrumNetwork ||
+		ch.id.Cmp(config.ArbitrumOneChainID) == 0 ||
+		ch.id.Cmp(config.ArbitrumGoerliChainID) == 0 ||
+		ch.id.Cmp(config.ArbitrumSepoliaChainID) == 0
+}
+
+// isOpStack returns true if the chain is a known OP Stack network or is set as one in its config.
+func (ch *chain) isOpStack() bool {
+	return ch.conf.IsOpStackNetwork || config.OpStackChains.Contains(ch.id.Uint64())
+}
+
+// newOverheads returns the overhead for each EntryPoint v0.6 of the chain.
+func (ch *chain) newOverheads() estimator.Overheads {
+	ovs := estimator.Overheads{}
+	for ep := range ch.conf.EntryPointToPaymasters {
+		if ch.v07[ep] {
+			continue
+		}
+
+		ov := gas.NewDefaultOverhead()
+		if ch.isArbitrum() {
+			ov.SetCalcPreVerificationGasFunc(gas.CalcArbitrumPVGWithEthClient(ch.rpc, ep))
+			ov.SetPreVerificationGasBufferFactor(16)
+		}
+		if ch.isOpStack() {
+			ov.SetCalcPreVerificationGasFunc(estimator.CalcOptimismPVGWithEthClient(ch.rpc, ch.id, ep))
+			ov.SetPreVerificationGasBufferFactor(1)
+		}
+		ovs[ep] = ov
+	}
+	return ovs
+}
+
+// newOverheadV07 is the EntryPoint v0.7 equivalent of newOverhead.
+func (ch *chain) newOverheadV07() *estimator.OverheadV07 {
+	ov := estimator.NewDefaultOverheadV07()
+	if ch.isArbitrum() {
+		ov.SetCalcPreVerificationGasFunc(estimator.CalcArbitrumPVGV07WithEthClient(ch.rpc))
+		ov.SetPreVerificationGasBufferFactor(16)
+	}
+	if ch.isOpStack() {
+		ov.SetCalcPreVerificationGasFunc(estimator.CalcOptimismPVGV07WithEthClient(ch.rpc, ch.id))
+		ov.SetPreVerificationGasBufferFactor(1)
+	}
+	return ov
+}
+
+// newClient sets up all services for the chain and returns a Client to serve its requests.
+func (ch *chain) newClient(
+	conf *config.Values,
+	policies *policy.Store,
+	access *accesslist.Store,
+	l logr.Logger,
+) (*client.Client, error) {
+	if err := ch.openDB(conf); err != nil {
+		return nil, err
+	}
+
+	o, err := oracle.New(
+		ch.eth,
+		conf.ERC20TokenToExchangeRate,
+		conf.ERC20TokenToOracleSource,
+		&oracle.Opts{
+			MarkupBps:    conf.OracleMarkupBps,
+			CacheTTL:     conf.OracleCacheTTL,
+			MaxStaleness: conf.OracleMaxStaleness,
+			TWAPWindow:   conf.OracleTWAPWindow,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	globalBudgetPeriod, err := budget.ParsePeriod(conf.GlobalBudgetPeriod)
+	if err != nil {
+		return nil, err
+	}
+	senderBudgetPeriod, err := budget.ParsePeriod(conf.SenderBudgetPeriod)
+	if err != nil {
+		return nil, err
+	}
+	// Budgets are in the chain's native token so each chain has its own tracker in its own DB.
+	budgets := budget.New(
+		ch.db,
+		&budget.Limit{Amount: ch.conf.GlobalBudget, Period: globalBudgetPeriod},
+		&budget.Limit{Amount: ch.conf.SenderBudget, Period: senderBudgetPeriod},
+	)
+
+	ep2pms := ch.conf.EntryPointToPaymasters
+	var w *watcher.Watcher
+	if conf.WatcherInterval > 0 {
+		w = watcher.New(ch.db, ch.eth, ep2pms, budgets, l.WithName("watcher"))
+		w.Run(conf.WatcherInterval)
+	}
+
+	verifiers := keyring.New(ch.eth, ch.signers, l.WithName("keyring"))
+	pms := []common.Address{}
+	for _, eps := range ep2pms {
+		pms = append(pms, eps...)
+	}
+	if err := verifiers.Refresh(pms); err != nil {
+		l.Error(err, "keyring refresh error")
+	}
+	if conf.VerifierRefreshInterval > 0 {
+		verifiers.Watch(pms, conf.VerifierRefreshInterval)
+	}
+
+	var deposits *deposit.Monitor
+	if conf.DepositMonitorInterval > 0 {
+		deposits = deposit.New(
+			ch.eth,
+			ep2pms,
+			&deposit.Opts{
+				ChainID:    ch.id,
+				MinDeposit: conf.MinDeposit,
+				MinStake:   conf.MinStake,
+				Enforce:    conf.EnforceDeposit,
+			},
+			l.WithName("deposit_monitor"),
+		)
+		if err := deposits.Run(conf.DepositMonitorInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	strategy, err := selector.ParseStrategy(conf.PaymasterSelectionStrategy)
+	if err != nil {
+		return nil, err
+	}
+	sel, err := selector.New(strategy, deposits)
+	if err != nil {
+		return nil, err
+	}
+
+	var fs *fees.Suggester
+	if ch.conf.FeeStrategy != "" {
+		feeStrategy, err := fees.ParseStrategy(ch.conf.FeeStrategy)
+		if err != nil {
+			return nil, err
+		}
+		fs = fees.New(ch.eth, feeStrategy, &fees.Opts{
+			Blocks:               conf.FeeHistoryBlocks,
+			Percentile:           conf.FeeHistoryPercentile,
+			BaseFeeBufferPercent: conf.BaseFeeBufferPercent,
+		})
+	}
+
+	var v *validation.Validator
+	if conf.SimulateValidation {
+		v = validation.New(ch.eth, ch.id)
+	}
+
+	return client.New(
+		verifiers,
+		ch.rpc,
+		ch.eth,
+		ch.id,
+		ch.newOverheads(),
+		ch.newOverheadV07(),
+		ep2pms,
+		ch.v07,
+		o,
+		policies,
+		access,
+		budgets,
+		w,
+		deposits,
+		sel,
+		fs,
+		v,
+		&handlers.ValidityConfig{
+			ValidFor:           conf.ValidFor,
+			MaxValidFor:        conf.MaxValidFor,
+			MaxValidAfterDelay: conf.MaxValidAfterDelay,
+		},
+		&handlers.PaymasterGasLimits{
+			VerificationGasLimit: ch.conf.V07PaymasterVerificationGasLimit,
+			PostOpGasLimit:       ch.conf.V07PaymasterPostOpGasLimit,
+		},
+		ch.conf.Gas,
+		l,
+	), nil
+}
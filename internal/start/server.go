@@ -1,22 +1,23 @@
 package start
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/stackup-wallet/stackup-bundler/pkg/gas"
 	"github.com/stackup-wallet/stackup-bundler/pkg/jsonrpc"
-	"github.com/stackup-wallet/stackup-bundler/pkg/signer"
 	"github.com/stackup-wallet/stackup-paymaster/internal/config"
 	"github.com/stackup-wallet/stackup-paymaster/internal/logger"
 	"github.com/stackup-wallet/stackup-paymaster/internal/o11y"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/accesslist"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/apikey"
 	"github.com/stackup-wallet/stackup-paymaster/pkg/client"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
@@ -25,20 +26,13 @@ func Server() {
 
 	logr := logger.NewZeroLogr().WithName("stackup_paymaster")
 
-	signer, err := signer.New(conf.SigningKey)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	rpc, err := rpc.Dial(conf.EthClientUrl)
-	if err != nil {
-		log.Fatal(err)
-	}
-	eth := ethclient.NewClient(rpc)
-
-	chain, err := eth.ChainID(context.Background())
-	if err != nil {
-		log.Fatal(err)
+	chains := []*chain{}
+	for _, cv := range conf.Chains {
+		ch, err := dialChain(cv)
+		if err != nil {
+			log.Fatal(err)
+		}
+		chains = append(chains, ch)
 	}
 
 	if o11y.IsEnabled(conf.OTELServiceName) {
@@ -47,9 +41,10 @@ func Server() {
 			CollectorHeader: conf.OTELCollectorHeaders,
 			CollectorUrl:    conf.OTELCollectorUrl,
 			InsecureMode:    conf.OTELInsecureMode,
-
-			ChainID:       chain,
-			SignerAddress: signer.Address,
+		}
+		if len(chains) == 1 {
+			o11yOpts.ChainID = chains[0].id
+			o11yOpts.SignerAddress = chains[0].signers[0].Address()
 		}
 
 		tracerCleanup := o11y.InitTracer(o11yOpts)
@@ -59,22 +54,72 @@ func Server() {
 		defer metricsCleanup()
 	}
 
-	ov := gas.NewDefaultOverhead()
-	if chain.Cmp(config.ArbitrumOneChainID) == 0 ||
-		chain.Cmp(config.ArbitrumGoerliChainID) == 0 ||
-		chain.Cmp(config.ArbitrumSepoliaChainID) == 0 {
-		ov.SetCalcPreVerificationGasFunc(gas.CalcArbitrumPVGWithEthClient(rpc, conf.DefaultEntryPoint))
-		ov.SetPreVerificationGasBufferFactor(16)
+	var policies *policy.Store
+	var err error
+	if conf.PoliciesFile != "" {
+		policies, err = policy.Load(conf.PoliciesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 
-	if conf.IsOpStackNetwork || config.OpStackChains.Contains(chain.Uint64()) {
-		ov.SetCalcPreVerificationGasFunc(
-			gas.CalcOptimismPVGWithEthClient(rpc, chain, conf.DefaultEntryPoint),
-		)
-		ov.SetPreVerificationGasBufferFactor(1)
+	var access *accesslist.Store
+	if conf.AccessListSource != "" {
+		access, err = accesslist.Load(conf.AccessListSource)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if conf.AccessListReloadInterval > 0 {
+			access.Watch(conf.AccessListReloadInterval, logr.WithName("access_list"))
+		}
 	}
 
-	c := client.New(signer, rpc, eth, chain, ov, conf.EntryPointToPaymasters, logr)
+	clients := client.Chains{}
+	for _, ch := range chains {
+		if _, ok := clients[ch.id.Uint64()]; ok {
+			log.Fatalf("Fatal config error: duplicate chain %s", ch.id)
+		}
+
+		c, err := ch.newClient(conf, policies, access, logr.WithValues("chain_id", ch.id.String()))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer ch.db.Close()
+		clients.Add(c)
+	}
+
+	var keys *apikey.Store
+	if conf.APIKeysFile != "" {
+		keys, err = apikey.Load(conf.APIKeysFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if conf.APIKeysReloadInterval > 0 {
+			keys.Watch(conf.APIKeysReloadInterval, logr.WithName("api_keys"))
+		}
+	}
+
+	// Reload all hot reloadable config on SIGHUP.
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			if keys != nil {
+				if err := keys.Reload(); err != nil {
+					logr.Error(err, "api keys reload error")
+				} else {
+					logr.Info("api keys reloaded")
+				}
+			}
+			if access != nil {
+				if err := access.Reload(); err != nil {
+					logr.Error(err, "access list reload error")
+				} else {
+					logr.Info("access list reloaded")
+				}
+			}
+		}
+	}()
 
 	gin.SetMode(conf.GinMode)
 	r := gin.New()
@@ -93,11 +138,24 @@ func Server() {
 		g.Status(http.StatusOK)
 	})
 	handlers := []gin.HandlerFunc{
-		jsonrpc.Controller(client.NewRpcAdapter(c)),
+		client.ERC7677Middleware(),
+		clients.Controller(),
 		jsonrpc.WithOTELTracerAttributes(),
 	}
+	if keys != nil {
+		handlers = append([]gin.HandlerFunc{keys.Middleware()}, handlers...)
+	}
 	r.POST("/", handlers...)
 	r.POST("/rpc", handlers...)
+	switch {
+	case conf.ChainsFile != "" && keys != nil:
+		r.POST("/rpc/:chainId", handlers...)
+		r.POST("/rpc/:chainId/:key", handlers...)
+	case conf.ChainsFile != "":
+		r.POST("/rpc/:chainId", handlers...)
+	case keys != nil:
+		r.POST("/rpc/:key", handlers...)
+	}
 
 	if err := r.Run(fmt.Sprintf(":%d", conf.Port)); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,22 @@
+package yamlutil
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Unmarshal decodes a YAML or JSON document into v. YAML is a superset of JSON so both formats can be parsed
+// with the same decoder.
+func Unmarshal(b []byte, v any) error {
+	return yaml.Unmarshal(b, v)
+}
+
+// ReadFile decodes the YAML or JSON file at path into v.
+func ReadFile(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return Unmarshal(b, v)
+}
@@ -0,0 +1,188 @@
+// Package accesslist blocks UserOperations by sender, factory, or call target. Unlike policies, the lists
+// apply to all requests and are meant to be updated quickly without a restart.
+package accesslist
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-logr/logr"
+	"github.com/stackup-wallet/stackup-paymaster/internal/yamlutil"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/addrset"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/calldata"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+// ErrBlocked is wrapped by all errors returned when a UserOperation is blocked by an access list.
+var ErrBlocked = errors.New("accesslist: blocked")
+
+const fetchTimeout = 10 * time.Second
+
+// BlockedData is the data field of the JSON-RPC error returned when an op is blocked.
+type BlockedData struct {
+	// Field is one of "sender", "factory", or "target".
+	Field   string         `json:"field"`
+	Address common.Address `json:"address"`
+}
+
+type file struct {
+	Senders   *addrset.Set `json:"senders"   yaml:"senders"`
+	Factories *addrset.Set `json:"factories" yaml:"factories"`
+	Targets   *addrset.Set `json:"targets"   yaml:"targets"`
+}
+
+// Store holds the access lists loaded from a file or HTTP endpoint. The lists can be reloaded at any time
+// without interrupting requests.
+type Store struct {
+	source string
+	mu     sync.RWMutex
+	lists  *file
+	hash   [32]byte
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func read(source string) ([]byte, error) {
+	if !isURL(source) {
+		return os.ReadFile(source)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("accesslist: status %d from %s", res.StatusCode, source)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// Load returns a Store with the access lists from a YAML or JSON file. The source can be either a file
+// path or an http(s) URL.
+func Load(source string) (*Store, error) {
+	s := &Store{source: source}
+	if _, err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// reload reads the source again and replaces all lists. It returns false if the source has not changed.
+func (s *Store) reload() (bool, error) {
+	b, err := read(s.source)
+	if err != nil {
+		return false, err
+	}
+	hash := sha256.Sum256(b)
+	s.mu.RLock()
+	changed := s.lists == nil || hash != s.hash
+	s.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	var f file
+	if err := yamlutil.Unmarshal(b, &f); err != nil {
+		return false, fmt.Errorf("accesslist: %s", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists = &f
+	s.hash = hash
+	return true, nil
+}
+
+// Reload reads the source again and replaces all lists.
+func (s *Store) Reload() error {
+	_, err := s.reload()
+	return err
+}
+
+// Watch reloads the source at the given interval in a separate goroutine. The lists are only replaced if
+// the source has changed.
+func (s *Store) Watch(interval time.Duration, l logr.Logger) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			changed, err := s.reload()
+			if err != nil {
+				l.Error(err, "access list reload error")
+				continue
+			}
+			if changed {
+				l.Info("access list reloaded")
+			}
+		}
+	}()
+}
+
+func block(field string, addr common.Address) error {
+	return rpcerrors.New(
+		rpcerrors.CodeBlocked,
+		fmt.Errorf("%w: %s %s", ErrBlocked, field, addr.Hex()),
+		&BlockedData{Field: field, Address: addr},
+	)
+}
+
+// Check returns an error wrapping ErrBlocked if the sender, factory, or any call target of an op is
+// blocked. The factory is ignored if it is the zero address. If the callData can't be decoded, the op is
+// only blocked if there is an allow list for targets. A nil Store will allow all ops.
+func (s *Store) Check(sender common.Address, factory common.Address, callData []byte) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	ls := s.lists
+	s.mu.RUnlock()
+
+	if !ls.Senders.IsAllowed(sender) {
+		return block("sender", sender)
+	}
+	if factory != (common.Address{}) && !ls.Factories.IsAllowed(factory) {
+		return block("factory", factory)
+	}
+	if ls.Targets.IsEmpty() {
+		return nil
+	}
+
+	calls, err := calldata.Decode(callData)
+	if err != nil {
+		if ls.Targets.HasAllowList() {
+			return rpcerrors.New(
+				rpcerrors.CodeBlocked,
+				fmt.Errorf("%w: cannot decode callData: %s", ErrBlocked, err),
+				nil,
+			)
+		}
+		return nil
+	}
+	for _, call := range calls {
+		if !ls.Targets.IsAllowed(call.Target) {
+			return block("target", call.Target)
+		}
+	}
+	return nil
+}
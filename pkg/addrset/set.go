@@ -0,0 +1,62 @@
+// Package addrset allows or denies a set of addresses. It is shared by policies and access lists so that
+// both follow the same rules.
+package addrset
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// Set allows or denies a set of addresses. An address in the deny list is always rejected. If the allow list
+// is not empty, any address not in it is also rejected. It is decoded from an object with "allow" and "deny"
+// lists of addresses.
+type Set struct {
+	allow map[common.Address]bool
+	deny  map[common.Address]bool
+}
+
+// New returns a Set with the given allow and deny lists.
+func New(allow []common.Address, deny []common.Address) *Set {
+	s := &Set{allow: map[common.Address]bool{}, deny: map[common.Address]bool{}}
+	for _, addr := range allow {
+		s.allow[addr] = true
+	}
+	for _, addr := range deny {
+		s.deny[addr] = true
+	}
+	return s
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *Set) UnmarshalYAML(value *yaml.Node) error {
+	var lists struct {
+		Allow []common.Address `yaml:"allow"`
+		Deny  []common.Address `yaml:"deny"`
+	}
+	if err := value.Decode(&lists); err != nil {
+		return err
+	}
+	*s = *New(lists.Allow, lists.Deny)
+	return nil
+}
+
+// IsAllowed returns true if the address is not rejected by the Set. A nil Set allows all addresses.
+func (s *Set) IsAllowed(addr common.Address) bool {
+	if s == nil {
+		return true
+	}
+	if s.deny[addr] {
+		return false
+	}
+	return len(s.allow) == 0 || s.allow[addr]
+}
+
+// IsEmpty returns true if the Set has no addresses. A nil Set is empty.
+func (s *Set) IsEmpty() bool {
+	return s == nil || len(s.allow) == 0 && len(s.deny) == 0
+}
+
+// HasAllowList returns true if only the addresses in the allow list are allowed.
+func (s *Set) HasAllowList() bool {
+	return s != nil && len(s.allow) > 0
+}
@@ -0,0 +1,74 @@
+package apikey
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/budget"
+)
+
+// Budget is the max amount of wei that can be sponsored for an API key within a period. The amount is in the
+// native token of each chain and is tracked separately for each chain the key is used on.
+type Budget struct {
+	Amount *big.Int      `json:"amount" yaml:"amount"`
+	Period budget.Period `json:"period" yaml:"period"`
+}
+
+// Key is the configuration for a single tenant of the paymaster.
+type Key struct {
+	Name        string           `json:"name"        yaml:"name"`
+	Key         string           `json:"key"         yaml:"key"`
+	EntryPoints []common.Address `json:"entryPoints" yaml:"entryPoints"`
+	Policies    []string         `json:"policies"    yaml:"policies"`
+	RateLimit   float64          `json:"rateLimit"   yaml:"rateLimit"`
+	RateBurst   int              `json:"rateBurst"   yaml:"rateBurst"`
+	Budget      *Budget          `json:"budget"      yaml:"budget"`
+}
+
+// AllowsEntryPoint returns true if the key can be used with the given EntryPoint. A nil key or a key
+// without any EntryPoints configured allows all EntryPoints.
+func (k *Key) AllowsEntryPoint(ep common.Address) bool {
+	if k == nil || len(k.EntryPoints) == 0 {
+		return true
+	}
+
+	for _, allowed := range k.EntryPoints {
+		if allowed == ep {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyID returns the policy that should be used for a request. If the request does not specify one, the
+// first policy configured for the key is used. The second return value is false if the policy is not
+// allowed for the key.
+func (k *Key) PolicyID(requested string) (string, bool) {
+	if k == nil || len(k.Policies) == 0 {
+		return requested, true
+	}
+	if requested == "" {
+		return k.Policies[0], true
+	}
+
+	for _, allowed := range k.Policies {
+		if allowed == requested {
+			return requested, true
+		}
+	}
+	return requested, false
+}
+
+// BudgetScopes returns the budget scopes that apply to the key. A nil key returns no scopes.
+func (k *Key) BudgetScopes() []*budget.Scope {
+	if k == nil || k.Budget == nil {
+		return nil
+	}
+
+	return []*budget.Scope{
+		{
+			Name:  "apikey:" + k.Name,
+			Limit: &budget.Limit{Amount: k.Budget.Amount, Period: k.Budget.Period},
+		},
+	}
+}
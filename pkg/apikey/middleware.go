@@ -0,0 +1,58 @@
+package apikey
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderName is the request header that can be used to pass an API key instead of a path segment.
+	HeaderName = "X-Api-Key"
+
+	contextKey = "api-key"
+)
+
+func abort(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"jsonrpc": "2.0",
+		"error": gin.H{
+			"code":    -32600,
+			"message": message,
+			"data":    nil,
+		},
+		"id": nil,
+	})
+}
+
+// Middleware returns a Gin middleware that authenticates requests with an API key from either the "key"
+// path parameter or the X-Api-Key header. Requests over the rate limit of the key are rejected.
+func (s *Store) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.Param("key")
+		if secret == "" {
+			secret = c.GetHeader(HeaderName)
+		}
+
+		e, ok := s.get(secret)
+		if secret == "" || !ok {
+			abort(c, http.StatusUnauthorized, "Unauthorized: invalid API key")
+			return
+		}
+		if e.limiter != nil && !e.limiter.Allow() {
+			abort(c, http.StatusTooManyRequests, "Too many requests")
+			return
+		}
+
+		c.Set(contextKey, e.key)
+		c.Next()
+	}
+}
+
+// FromContext returns the API key for the request. It returns nil if authentication is not enabled.
+func FromContext(c *gin.Context) *Key {
+	if k, ok := c.Get(contextKey); ok {
+		return k.(*Key)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package apikey
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stackup-wallet/stackup-paymaster/internal/yamlutil"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/budget"
+	"golang.org/x/time/rate"
+)
+
+type file struct {
+	Keys []*Key `json:"keys" yaml:"keys"`
+}
+
+type entry struct {
+	key     *Key
+	limiter *rate.Limiter
+}
+
+// Store holds all API keys loaded from a file. The file can be reloaded at any time without interrupting
+// requests.
+type Store struct {
+	path    string
+	mu      sync.RWMutex
+	keys    map[string]*entry
+	modTime time.Time
+}
+
+func parse(path string) (map[string]*Key, error) {
+	var f file
+	if err := yamlutil.ReadFile(path, &f); err != nil {
+		return nil, fmt.Errorf("apikey: %s", err)
+	}
+
+	keys := map[string]*Key{}
+	for _, k := range f.Keys {
+		if k.Name == "" || k.Key == "" {
+			return nil, fmt.Errorf("apikey: missing name or key in %s", path)
+		}
+		if _, ok := keys[k.Key]; ok {
+			return nil, fmt.Errorf("apikey: duplicate key for %s in %s", k.Name, path)
+		}
+		if k.Budget != nil {
+			if _, err := budget.ParsePeriod(string(k.Budget.Period)); err != nil {
+				return nil, fmt.Errorf("apikey: %s: %s", k.Name, err)
+			}
+		}
+		keys[k.Key] = k
+	}
+	return keys, nil
+}
+
+// Load returns a Store with all API keys from a YAML or JSON file.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, keys: map[string]*entry{}}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads the file again and replaces all keys. Rate limiters are kept for keys that have the same
+// limits as before.
+func (s *Store) Reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	keys, err := parse(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := map[string]*entry{}
+	for secret, k := range keys {
+		var limiter *rate.Limiter
+		if prev, ok := s.keys[secret]; ok &&
+			prev.key.RateLimit == k.RateLimit &&
+			prev.key.RateBurst == k.RateBurst {
+			limiter = prev.limiter
+		} else if k.RateLimit > 0 {
+			limiter = rate.NewLimiter(rate.Limit(k.RateLimit), max(k.RateBurst, 1))
+		}
+		next[secret] = &entry{key: k, limiter: limiter}
+	}
+	s.keys = next
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// Watch checks the file for changes at the given interval in a separate goroutine and reloads it when it
+// has been modified.
+func (s *Store) Watch(interval time.Duration, l logr.Logger) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			info, err := os.Stat(s.path)
+			if err != nil {
+				l.Error(err, "api keys reload error")
+				continue
+			}
+
+			s.mu.RLock()
+			modified := !info.ModTime().Equal(s.modTime)
+			s.mu.RUnlock()
+			if !modified {
+				continue
+			}
+
+			if err := s.Reload(); err != nil {
+				l.Error(err, "api keys reload error")
+				continue
+			}
+			l.Info("api keys reloaded")
+		}
+	}()
+}
+
+func (s *Store) get(secret string) (*entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.keys[secret]
+	return e, ok
+}
@@ -0,0 +1,44 @@
+package budget
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period is the length of time before a budget is reset.
+type Period string
+
+const (
+	Day   Period = "day"
+	Week  Period = "week"
+	Month Period = "month"
+)
+
+// ParsePeriod returns a Period from a string.
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(s); p {
+	case Day, Week, Month:
+		return p, nil
+	default:
+		return "", fmt.Errorf("budget: period %s not recognized", s)
+	}
+}
+
+// window returns a key for the period that t falls in and the time at which that period ends. All
+// periods are in UTC.
+func (p Period) window(t time.Time) (string, time.Time) {
+	t = t.UTC()
+	switch p {
+	case Week:
+		y, w := t.ISOWeek()
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).
+			AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+		return fmt.Sprintf("%d-W%02d", y, w), start.AddDate(0, 0, 7)
+	case Month:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start.Format("2006-01"), start.AddDate(0, 1, 0)
+	default:
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return start.Format("2006-01-02"), start.AddDate(0, 0, 1)
+	}
+}
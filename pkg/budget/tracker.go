@@ -0,0 +1,239 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stackup-wallet/stackup-paymaster/internal/dbutils"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+var (
+	keyPrefix = dbutils.JoinValues("budget")
+
+	// ErrCodeBudgetExceeded is the JSON-RPC error code returned when a sponsorship would exceed a budget.
+	ErrCodeBudgetExceeded = rpcerrors.CodeBudgetExceeded
+
+	// ErrCodeBudgetBusy is the JSON-RPC error code returned when a budget update keeps conflicting with
+	// concurrent requests.
+	ErrCodeBudgetBusy = rpcerrors.CodeBudgetBusy
+)
+
+// maxUpdateAttempts is the number of times a budget update is tried before giving up on conflicts.
+const maxUpdateAttempts = 5
+
+// Limit is the max amount of wei that can be sponsored within a Period. A nil Limit or a Limit with a nil
+// Amount is unlimited.
+type Limit struct {
+	Amount *big.Int
+	Period Period
+}
+
+// Scope is a named budget with its own Limit (e.g. a single sender or a single API key).
+type Scope struct {
+	Name  string
+	Limit *Limit
+}
+
+// Reservation is a record of wei reserved against one or more budgets. It can be used to release unused
+// amounts at a later time.
+type Reservation struct {
+	Keys      []string `json:"keys"`
+	Amount    *big.Int `json:"amount"`
+	ExpiresAt int64    `json:"expiresAt"`
+}
+
+// ExceededData is the data field of the JSON-RPC error returned when a budget is exceeded.
+type ExceededData struct {
+	Scope     string `json:"scope"`
+	Period    Period `json:"period"`
+	Limit     string `json:"limit"`
+	Remaining string `json:"remaining"`
+}
+
+// Tracker records the amount of wei sponsored against a global budget, a budget per sender, and any other
+// scopes given at the time of reservation.
+type Tracker struct {
+	db        *badger.DB
+	global    *Limit
+	perSender *Limit
+}
+
+// New returns a Tracker that persists to the given DB.
+func New(db *badger.DB, global *Limit, perSender *Limit) *Tracker {
+	return &Tracker{
+		db:        db,
+		global:    global,
+		perSender: perSender,
+	}
+}
+
+func getUsed(txn *badger.Txn, key []byte) (*big.Int, error) {
+	used := big.NewInt(0)
+	item, err := txn.Get(key)
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return used, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = item.Value(func(val []byte) error {
+		used.SetBytes(val)
+		return nil
+	})
+	return used, err
+}
+
+func setUsed(txn *badger.Txn, key []byte, used *big.Int, deadline time.Time) error {
+	e := badger.NewEntry(key, used.Bytes()).WithTTL(time.Until(deadline))
+	return txn.SetEntry(e)
+}
+
+// update runs fn in a read-write transaction and retries if it conflicts with a concurrent request. An
+// error with ErrCodeBudgetBusy is returned if it still conflicts after maxUpdateAttempts.
+func (t *Tracker) update(fn func(txn *badger.Txn) error) error {
+	for i := 0; i < maxUpdateAttempts; i++ {
+		err := t.db.Update(fn)
+		if !errors.Is(err, badger.ErrConflict) {
+			return err
+		}
+	}
+	return rpcerrors.New(
+		ErrCodeBudgetBusy,
+		fmt.Errorf("budget: update conflicted %d times: %w", maxUpdateAttempts, badger.ErrConflict),
+		nil,
+	)
+}
+
+// scopes returns the global and sender budgets followed by any other scopes.
+func (t *Tracker) scopes(sender common.Address, scopes []*Scope) []*Scope {
+	return append(
+		[]*Scope{{Name: "global", Limit: t.global}, {Name: "sender:" + sender.Hex(), Limit: t.perSender}},
+		scopes...,
+	)
+}
+
+// add returns the new amount used for a scope if amount is added to it. If the budget would be exceeded an
+// error with ErrCodeBudgetExceeded is returned instead.
+func add(txn *badger.Txn, s *Scope, key string, amount *big.Int) (*big.Int, error) {
+	used, err := getUsed(txn, []byte(key))
+	if err != nil {
+		return nil, err
+	}
+
+	total := big.NewInt(0).Add(used, amount)
+	if total.Cmp(s.Limit.Amount) > 0 {
+		remaining := big.NewInt(0).Sub(s.Limit.Amount, used)
+		if remaining.Sign() < 0 {
+			remaining = big.NewInt(0)
+		}
+		return nil, rpcerrors.New(
+			ErrCodeBudgetExceeded,
+			fmt.Errorf("budget: %s exceeded", s.Name),
+			&ExceededData{
+				Scope:     s.Name,
+				Period:    s.Limit.Period,
+				Limit:     s.Limit.Amount.String(),
+				Remaining: remaining.String(),
+			},
+		)
+	}
+	return total, nil
+}
+
+// Check returns an error with ErrCodeBudgetExceeded if reserving amount for the sender would exceed any
+// budget. Unlike Reserve, no budgets are updated.
+func (t *Tracker) Check(sender common.Address, amount *big.Int, scopes ...*Scope) error {
+	if t == nil {
+		return nil
+	}
+
+	return t.db.View(func(txn *badger.Txn) error {
+		for _, s := range t.scopes(sender, scopes) {
+			if s.Limit == nil || s.Limit.Amount == nil {
+				continue
+			}
+
+			window, _ := s.Limit.Period.window(time.Now())
+			if _, err := add(txn, s, dbutils.JoinValues(keyPrefix, s.Name, window), amount); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Reserve atomically adds amount to all budgets that apply to the sender. If any budget would be exceeded,
+// no budgets are updated and an error with ErrCodeBudgetExceeded is returned.
+func (t *Tracker) Reserve(sender common.Address, amount *big.Int, scopes ...*Scope) (*Reservation, error) {
+	if t == nil {
+		return &Reservation{Amount: amount}, nil
+	}
+
+	res := &Reservation{Keys: []string{}, Amount: amount}
+	err := t.update(func(txn *badger.Txn) error {
+		res.Keys, res.ExpiresAt = []string{}, 0
+		for _, s := range t.scopes(sender, scopes) {
+			if s.Limit == nil || s.Limit.Amount == nil {
+				continue
+			}
+
+			window, expiresAt := s.Limit.Period.window(time.Now())
+			key := dbutils.JoinValues(keyPrefix, s.Name, window)
+			total, err := add(txn, s, key, amount)
+			if err != nil {
+				return err
+			}
+			if err := setUsed(txn, []byte(key), total, expiresAt.Add(time.Hour)); err != nil {
+				return err
+			}
+
+			res.Keys = append(res.Keys, key)
+			if res.ExpiresAt == 0 || expiresAt.Unix() < res.ExpiresAt {
+				res.ExpiresAt = expiresAt.Unix()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Release refunds an amount from a previous Reservation back to all of its budgets. It is a no-op if the
+// budget periods of the Reservation have already ended.
+func (t *Tracker) Release(res *Reservation, amount *big.Int) error {
+	if t == nil || time.Now().Unix() >= res.ExpiresAt {
+		return nil
+	}
+
+	return t.update(func(txn *badger.Txn) error {
+		for _, key := range res.Keys {
+			item, err := txn.Get([]byte(key))
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			used, err := getUsed(txn, []byte(key))
+			if err != nil {
+				return err
+			}
+			used.Sub(used, amount)
+			if used.Sign() < 0 {
+				used = big.NewInt(0)
+			}
+			if err := setUsed(txn, []byte(key), used, time.Unix(int64(item.ExpiresAt()), 0)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
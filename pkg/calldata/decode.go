@@ -0,0 +1,101 @@
+package calldata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	// ErrUnknownMethod is returned when the callData does not match any supported account method.
+	ErrUnknownMethod = errors.New("calldata: unknown method")
+)
+
+// Call is a single call made by a smart account to a target contract.
+type Call struct {
+	Target       common.Address
+	Value        *big.Int
+	Data         []byte
+	DelegateCall bool
+}
+
+// Selector returns the function selector of the call. If the call has no data, it returns an empty
+// selector.
+func (c *Call) Selector() [4]byte {
+	var sel [4]byte
+	if len(c.Data) >= 4 {
+		copy(sel[:], c.Data[:4])
+	}
+	return sel
+}
+
+// Args returns the ABI encoded arguments of the call without the function selector.
+func (c *Call) Args() []byte {
+	if len(c.Data) < 4 {
+		return []byte{}
+	}
+	return c.Data[4:]
+}
+
+// MarshalJSON returns a JSON encoding of the call with the selector and args split out from the data.
+func (c *Call) MarshalJSON() ([]byte, error) {
+	var sel hexutil.Bytes
+	if len(c.Data) >= 4 {
+		sel = c.Data[:4]
+	}
+	return json.Marshal(&struct {
+		Target       common.Address `json:"target"`
+		Value        *hexutil.Big   `json:"value"`
+		Selector     hexutil.Bytes  `json:"selector"`
+		Args         hexutil.Bytes  `json:"args"`
+		Data         hexutil.Bytes  `json:"data"`
+		DelegateCall bool           `json:"delegateCall"`
+	}{
+		Target:       c.Target,
+		Value:        (*hexutil.Big)(c.Value),
+		Selector:     sel,
+		Args:         c.Args(),
+		Data:         c.Data,
+		DelegateCall: c.DelegateCall,
+	})
+}
+
+// unpack returns the method and args for the callData if the selector is in the given ABI.
+func unpack(contract abi.ABI, callData []byte) (*abi.Method, []any, error) {
+	method, err := contract.MethodById(callData[:4])
+	if err != nil {
+		return nil, nil, ErrUnknownMethod
+	}
+	args, err := method.Inputs.Unpack(callData[4:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("calldata: %s", err)
+	}
+	return method, args, nil
+}
+
+// Decode unpacks the callData of a UserOperation into the list of calls that the account will make. The
+// supported accounts are SimpleAccount, Kernel, the Safe 4337 module, and any ERC-7579 account.
+func Decode(callData []byte) ([]*Call, error) {
+	if len(callData) < 4 {
+		return nil, ErrUnknownMethod
+	}
+
+	for _, decode := range []func([]byte) ([]*Call, error){
+		decodeSimpleAccount,
+		decodeKernel,
+		decodeSafe4337Module,
+		decodeERC7579,
+	} {
+		calls, err := decode(callData)
+		if errors.Is(err, ErrUnknownMethod) {
+			continue
+		}
+		return calls, err
+	}
+	return nil, ErrUnknownMethod
+}
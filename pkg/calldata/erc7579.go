@@ -0,0 +1,75 @@
+package calldata
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ERC-7579 call types from the first byte of the execution mode.
+const (
+	callTypeSingle       = 0x00
+	callTypeBatch        = 0x01
+	callTypeStatic       = 0xfe
+	callTypeDelegateCall = 0xff
+)
+
+type execution struct {
+	Target   common.Address
+	Value    *big.Int
+	CallData []byte
+}
+
+// decodeERC7579 unpacks the callData of an account that implements execute from ERC-7579.
+func decodeERC7579(callData []byte) ([]*Call, error) {
+	method, args, err := unpack(erc7579, callData)
+	if err != nil {
+		return nil, err
+	}
+	if method.RawName != "execute" {
+		return nil, ErrUnknownMethod
+	}
+
+	mode := args[0].([32]byte)
+	exec := args[1].([]byte)
+	switch mode[0] {
+	case callTypeSingle, callTypeStatic:
+		if len(exec) < 52 {
+			return nil, errors.New("calldata: bad ERC-7579 single execution")
+		}
+		return []*Call{
+			{
+				Target: common.BytesToAddress(exec[:20]),
+				Value:  new(big.Int).SetBytes(exec[20:52]),
+				Data:   exec[52:],
+			},
+		}, nil
+	case callTypeBatch:
+		batch, err := erc7579.Methods["executions"].Inputs.Unpack(exec)
+		if err != nil {
+			return nil, errors.New("calldata: bad ERC-7579 batch execution")
+		}
+
+		calls := []*Call{}
+		for _, e := range *abi.ConvertType(batch[0], new([]execution)).(*[]execution) {
+			calls = append(calls, &Call{Target: e.Target, Value: e.Value, Data: e.CallData})
+		}
+		return calls, nil
+	case callTypeDelegateCall:
+		if len(exec) < 20 {
+			return nil, errors.New("calldata: bad ERC-7579 delegatecall execution")
+		}
+		return []*Call{
+			{
+				Target:       common.BytesToAddress(exec[:20]),
+				Value:        big.NewInt(0),
+				Data:         exec[20:],
+				DelegateCall: true,
+			},
+		}, nil
+	default:
+		return nil, ErrUnknownMethod
+	}
+}
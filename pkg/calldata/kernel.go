@@ -0,0 +1,47 @@
+package calldata
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// operationDelegateCall is the value of the Operation enum used by Kernel and Safe for a delegatecall.
+const operationDelegateCall = 1
+
+type kernelCall struct {
+	To    common.Address
+	Value *big.Int
+	Data  []byte
+}
+
+// decodeKernel unpacks the callData of a Kernel v2 account. Kernel v3 is decoded as an ERC-7579 account.
+func decodeKernel(callData []byte) ([]*Call, error) {
+	method, args, err := unpack(kernel, callData)
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.RawName {
+	case "execute":
+		return []*Call{
+			{
+				Target:       args[0].(common.Address),
+				Value:        args[1].(*big.Int),
+				Data:         args[2].([]byte),
+				DelegateCall: args[3].(uint8) == operationDelegateCall,
+			},
+		}, nil
+	case "executeBatch":
+		batch := *abi.ConvertType(args[0], new([]kernelCall)).(*[]kernelCall)
+
+		calls := []*Call{}
+		for _, c := range batch {
+			calls = append(calls, &Call{Target: c.To, Value: c.Value, Data: c.Data})
+		}
+		return calls, nil
+	default:
+		return nil, ErrUnknownMethod
+	}
+}
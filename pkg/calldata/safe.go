@@ -0,0 +1,67 @@
+package calldata
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// decodeSafe4337Module unpacks the callData of a Safe with the 4337 module. A delegatecall to MultiSend is
+// unpacked into each of its transactions.
+func decodeSafe4337Module(callData []byte) ([]*Call, error) {
+	method, args, err := unpack(safe4337Module, callData)
+	if err != nil {
+		return nil, err
+	}
+	if method.RawName != "executeUserOp" && method.RawName != "executeUserOpWithErrorString" {
+		return nil, ErrUnknownMethod
+	}
+
+	call := &Call{
+		Target:       args[0].(common.Address),
+		Value:        args[1].(*big.Int),
+		Data:         args[2].([]byte),
+		DelegateCall: args[3].(uint8) == operationDelegateCall,
+	}
+	multiSend := safe4337Module.Methods["multiSend"]
+	if !call.DelegateCall || len(call.Data) < 4 || !bytes.Equal(call.Data[:4], multiSend.ID) {
+		return []*Call{call}, nil
+	}
+
+	msArgs, err := multiSend.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, errors.New("calldata: bad multiSend transactions")
+	}
+	return decodeMultiSend(msArgs[0].([]byte))
+}
+
+// decodeMultiSend unpacks the transactions of a MultiSend call. Each one is packed as operation (uint8), to
+// (address), value (uint256), data length (uint256), and data (bytes).
+func decodeMultiSend(txs []byte) ([]*Call, error) {
+	calls := []*Call{}
+	for i := 0; i < len(txs); {
+		if len(txs[i:]) < 85 {
+			return nil, errors.New("calldata: bad multiSend transactions")
+		}
+		op := txs[i]
+		to := common.BytesToAddress(txs[i+1 : i+21])
+		value := new(big.Int).SetBytes(txs[i+21 : i+53])
+		size := new(big.Int).SetBytes(txs[i+53 : i+85])
+		i += 85
+		if !size.IsUint64() || size.Uint64() > uint64(len(txs[i:])) {
+			return nil, errors.New("calldata: bad multiSend transactions")
+		}
+		n := int(size.Uint64())
+
+		calls = append(calls, &Call{
+			Target:       to,
+			Value:        value,
+			Data:         txs[i : i+n],
+			DelegateCall: op == operationDelegateCall,
+		})
+		i += n
+	}
+	return calls, nil
+}
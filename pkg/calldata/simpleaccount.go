@@ -0,0 +1,53 @@
+package calldata
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func decodeSimpleAccount(callData []byte) ([]*Call, error) {
+	method, args, err := unpack(simpleAccount, callData)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case method.RawName == "execute":
+		return []*Call{
+			{Target: args[0].(common.Address), Value: args[1].(*big.Int), Data: args[2].([]byte)},
+		}, nil
+	case method.RawName == "executeBatch" && len(args) == 2:
+		dest := args[0].([]common.Address)
+		data := args[1].([][]byte)
+		if len(dest) != len(data) {
+			return nil, errors.New("calldata: executeBatch length mismatch")
+		}
+
+		calls := []*Call{}
+		for i := range dest {
+			calls = append(calls, &Call{Target: dest[i], Value: big.NewInt(0), Data: data[i]})
+		}
+		return calls, nil
+	case method.RawName == "executeBatch" && len(args) == 3:
+		dest := args[0].([]common.Address)
+		value := args[1].([]*big.Int)
+		data := args[2].([][]byte)
+		if len(dest) != len(data) || (len(value) != 0 && len(value) != len(dest)) {
+			return nil, errors.New("calldata: executeBatch length mismatch")
+		}
+
+		calls := []*Call{}
+		for i := range dest {
+			v := big.NewInt(0)
+			if len(value) != 0 {
+				v = value[i]
+			}
+			calls = append(calls, &Call{Target: dest[i], Value: v, Data: data[i]})
+		}
+		return calls, nil
+	default:
+		return nil, ErrUnknownMethod
+	}
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stackup-wallet/stackup-bundler/pkg/jsonrpc"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/apikey"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+// Chains maps a chain ID to the Client that serves it.
+type Chains map[uint64]*Client
+
+// Add sets the Client for its chain.
+func (cs Chains) Add(c *Client) {
+	cs[c.chainID.Uint64()] = c
+}
+
+// get returns the Client for the chain of the request. The chain is taken from the "chainId" path parameter
+// or the chainId param of ERC-7677 methods, in that order. If neither are given, the request can only be
+// served if there is exactly one chain.
+func (cs Chains) get(g *gin.Context) (*Client, error) {
+	chainID := g.Param("chainId")
+	if chainID == "" {
+		chainID = g.GetString(erc7677ChainIDKey)
+	}
+	if chainID == "" {
+		if len(cs) == 1 {
+			for _, c := range cs {
+				return c, nil
+			}
+		}
+		return nil, fmt.Errorf("chainId: required")
+	}
+
+	id, ok := big.NewInt(0).SetString(chainID, 0)
+	if !ok || !id.IsUint64() {
+		return nil, fmt.Errorf("chainId: %s not supported", chainID)
+	}
+	c, ok := cs[id.Uint64()]
+	if !ok {
+		return nil, fmt.Errorf("chainId: %s not supported", chainID)
+	}
+	return c, nil
+}
+
+// Controller returns a Gin handler that serves each JSON-RPC request with the Client for its chain.
+func (cs Chains) Controller() gin.HandlerFunc {
+	return func(g *gin.Context) {
+		c, err := cs.get(g)
+		if err != nil {
+			g.AbortWithStatusJSON(http.StatusOK, gin.H{
+				"jsonrpc": "2.0",
+				"error": gin.H{
+					"code":    rpcerrors.CodeInvalidParams,
+					"message": err.Error(),
+					"data":    nil,
+				},
+				"id": nil,
+			})
+			return
+		}
+
+		jsonrpc.Controller(NewRpcAdapter(c, apikey.FromContext(g)))(g)
+	}
+}
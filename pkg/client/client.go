@@ -6,53 +6,107 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/go-logr/logr"
-	"github.com/stackup-wallet/stackup-bundler/pkg/gas"
-	"github.com/stackup-wallet/stackup-bundler/pkg/signer"
-	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/accesslist"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/apikey"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/budget"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/calldata"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/deposit"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/estimator"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/fees"
 	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers/erc20"
 	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers/payg"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/keyring"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/oracle"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/selector"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/validation"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/watcher"
 )
 
 type Client struct {
-	rpc         *rpc.Client
-	eth         *ethclient.Client
-	chainID     *big.Int
-	ov          *gas.Overhead
-	ep2pms      map[common.Address][]common.Address
-	paygHandler *payg.Handler
-	logger      logr.Logger
+	rpc          *rpc.Client
+	eth          *ethclient.Client
+	chainID      *big.Int
+	ov           estimator.Overheads
+	ep2pms       map[common.Address][]common.Address
+	v07          map[common.Address]bool
+	policies     *policy.Store
+	access       *accesslist.Store
+	budgets      *budget.Tracker
+	watcher      *watcher.Watcher
+	deposits     *deposit.Monitor
+	selector     *selector.Selector
+	fees         *fees.Suggester
+	keys         *keyring.Keyring
+	validator    *validation.Validator
+	validity     *handlers.ValidityConfig
+	paygHandler  *payg.Handler
+	erc20Handler *erc20.Handler
+	logger       logr.Logger
 }
 
 func New(
-	signer *signer.EOA,
+	keys *keyring.Keyring,
 	rpc *rpc.Client,
 	eth *ethclient.Client,
 	chain *big.Int,
-	ov *gas.Overhead,
+	ov estimator.Overheads,
+	ovV07 *estimator.OverheadV07,
 	ep2pms map[common.Address][]common.Address,
+	v07 map[common.Address]bool,
+	oracle oracle.Oracle,
+	policies *policy.Store,
+	access *accesslist.Store,
+	budgets *budget.Tracker,
+	watcher *watcher.Watcher,
+	deposits *deposit.Monitor,
+	selector *selector.Selector,
+	fees *fees.Suggester,
+	validator *validation.Validator,
+	validity *handlers.ValidityConfig,
+	pmGas *handlers.PaymasterGasLimits,
+	gasLimits *estimator.Limits,
 	l logr.Logger,
 ) *Client {
 	return &Client{
-		rpc:         rpc,
-		eth:         eth,
-		chainID:     chain,
-		ov:          ov,
-		ep2pms:      ep2pms,
-		paygHandler: payg.New(signer, rpc, eth, chain, ov),
-		logger:      l,
+		rpc:          rpc,
+		eth:          eth,
+		chainID:      chain,
+		ov:           ov,
+		ep2pms:       ep2pms,
+		v07:          v07,
+		policies:     policies,
+		access:       access,
+		budgets:      budgets,
+		watcher:      watcher,
+		deposits:     deposits,
+		selector:     selector,
+		fees:         fees,
+		keys:         keys,
+		validator:    validator,
+		validity:     validity,
+		paygHandler:  payg.New(keys, rpc, eth, chain, ov, ovV07, pmGas, gasLimits),
+		erc20Handler: erc20.New(keys, rpc, eth, chain, ov, ovV07, pmGas, gasLimits, oracle),
+		logger:       l,
 	}
 }
 
-func (c *Client) Accounts(ep string) ([]string, error) {
+func (c *Client) Accounts(ep string, key *apikey.Key) ([]string, error) {
 	l := c.logger.WithName("pm_accounts")
+	if key != nil {
+		l = l.WithValues("api_key", key.Name)
+	}
 
 	epAddr := common.HexToAddress(ep)
 	pmAddr, ok := c.ep2pms[epAddr]
-	if !ok {
-		err := errors.New("entryPoint: Implementation not supported")
+	if !ok || !key.AllowsEntryPoint(epAddr) {
+		err := rpcerrors.InvalidParams(errors.New("entryPoint: Implementation not supported"))
 		l.Error(err, "pm_accounts error")
 		return nil, err
 	}
@@ -69,52 +123,83 @@ func (c *Client) Accounts(ep string) ([]string, error) {
 	return res, nil
 }
 
+// DecodeCallData returns the calls that a smart account will make for the given callData.
+func (c *Client) DecodeCallData(callData string) ([]*calldata.Call, error) {
+	l := c.logger.WithName("pm_decodeCallData")
+
+	data, err := hexutil.Decode(callData)
+	if err != nil {
+		err = rpcerrors.InvalidParams(fmt.Errorf("bad callData: %s", err))
+		l.Error(err, "pm_decodeCallData error")
+		return nil, err
+	}
+	calls, err := calldata.Decode(data)
+	if err != nil {
+		err = rpcerrors.InvalidParams(err)
+		l.Error(err, "pm_decodeCallData error")
+		return nil, err
+	}
+
+	l.Info("pm_decodeCallData ok")
+	return calls, nil
+}
+
 func (c *Client) SponsorUserOperation(
 	op map[string]any,
 	ep string,
 	ctx map[string]any,
+	key *apikey.Key,
 ) (*handlers.SponsorUserOperationResponse, error) {
-	l := c.logger.WithName("pm_sponsorUserOperation")
-
-	epAddr := common.HexToAddress(ep)
-	pmAddrs, ok := c.ep2pms[epAddr]
-	if !ok {
-		err := errors.New("entryPoint: Implementation not supported")
+	req, l, err := c.parseRequest(op, ep, ctx, key, c.logger.WithName("pm_sponsorUserOperation"))
+	if err != nil {
 		l.Error(err, "pm_sponsorUserOperation error")
 		return nil, err
 	}
-	l = l.WithValues("entrypoint", epAddr.String()).
-		WithValues("paymasters", pmAddrs).
-		WithValues("chain_id", c.chainID.String())
-
-	userOp, err := userop.New(op)
+	suggested, err := c.suggestFees(req)
 	if err != nil {
-		err = fmt.Errorf("bad userOp: %s", err)
 		l.Error(err, "pm_sponsorUserOperation error")
 		return nil, err
 	}
-
-	ct, err := handlers.NewContextType(ctx)
-	if err != nil {
-		err = fmt.Errorf("bad context: %s", err)
+	if err := c.callWebhook(req, ctx, l); err != nil {
 		l.Error(err, "pm_sponsorUserOperation error")
 		return nil, err
 	}
-	l = l.WithValues("type", ct.Type)
 
-	switch ct.Type {
-	case "payg":
-		res, err := c.paygHandler.Run(userOp, epAddr, pmAddrs[0])
+	res, err := c.signAndValidate(req, func() (*handlers.SponsorUserOperationResponse, error) {
+		var res *handlers.SponsorUserOperationResponse
+		var err error
+		switch req.ctxType {
+		case "payg":
+			if req.packedOp != nil {
+				res, err = c.paygHandler.RunV07(req.packedOp, req.ep, req.pm, req.pol, req.val)
+			} else {
+				res, err = c.paygHandler.Run(req.userOp, req.ep, req.pm, req.pol, req.val)
+			}
+		case "erc20":
+			if req.packedOp != nil {
+				res, err = c.erc20Handler.RunV07(req.packedOp, req.ep, req.pm, req.token, req.pol, req.val)
+			} else {
+				res, err = c.erc20Handler.Run(req.userOp, req.ep, req.pm, req.token, req.pol, req.val)
+			}
+		}
 		if err != nil {
-			l.Error(err, "pm_sponsorUserOperation error")
 			return nil, err
 		}
-
-		l.Info("pm_sponsorUserOperation ok")
+		if suggested != nil {
+			res.MaxFeePerGas = hexutil.EncodeBig(suggested.MaxFeePerGas)
+			res.MaxPriorityFeePerGas = hexutil.EncodeBig(suggested.MaxPriorityFeePerGas)
+		}
 		return res, nil
-	default:
-		err := fmt.Errorf("type: %s not recognized", ct.Type)
+	})
+	if err != nil {
 		l.Error(err, "pm_sponsorUserOperation error")
 		return nil, err
 	}
+	if err := c.record(req, res, key); err != nil {
+		l.Error(err, "pm_sponsorUserOperation error")
+		return nil, err
+	}
+
+	l.Info("pm_sponsorUserOperation ok")
+	return res, nil
 }
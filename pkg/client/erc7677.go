@@ -0,0 +1,157 @@
+package client
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/stackup-wallet/stackup-paymaster/pkg/apikey"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+// stubDefaults are op fields that wallets may omit before gas estimation.
+var stubDefaults = map[string]any{
+	"callGasLimit":         "0x0",
+	"verificationGasLimit": "0x0",
+	"preVerificationGas":   "0x0",
+	"maxFeePerGas":         "0x0",
+	"maxPriorityFeePerGas": "0x0",
+	"paymasterAndData":     "0x",
+	"signature":            "0x",
+}
+
+// withDefaults returns a copy of the op with any missing fields set from the defaults.
+func withDefaults(op map[string]any, defaults map[string]any) map[string]any {
+	out := make(map[string]any, len(op))
+	for k, v := range op {
+		out[k] = v
+	}
+	for k, v := range defaults {
+		if _, ok := out[k]; !ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// erc7677Context converts an ERC-7677 context into a sponsorship context. ERC-7677 allows the context to be
+// empty or omit a type, in which case the request defaults to pay as you go sponsorship.
+func erc7677Context(ctx map[string]any) map[string]any {
+	return withDefaults(ctx, map[string]any{"type": "payg"})
+}
+
+// paymasterFromOp returns the paymaster already set on the op from a prior pm_getPaymasterStubData call. This
+// keeps pm_getPaymasterData on the same paymaster that the stub was estimated with.
+func paymasterFromOp(op map[string]any) (string, bool) {
+	if pm, ok := op["paymaster"].(string); ok && common.IsHexAddress(pm) {
+		return pm, true
+	}
+	if pnd, ok := op["paymasterAndData"].(string); ok && len(pnd) >= 42 && common.IsHexAddress(pnd[:42]) {
+		return pnd[:42], true
+	}
+	return "", false
+}
+
+func (c *Client) checkChainID(chainID string) error {
+	id, ok := big.NewInt(0).SetString(chainID, 0)
+	if !ok || id.Cmp(c.chainID) != 0 {
+		return rpcerrors.InvalidParams(fmt.Errorf("chainId: %s not supported", chainID))
+	}
+	return nil
+}
+
+// GetPaymasterStubData implements pm_getPaymasterStubData from ERC-7677. It returns paymaster fields with a
+// dummy signature that can be used by the wallet to estimate gas. No budgets are reserved at this stage.
+func (c *Client) GetPaymasterStubData(
+	op map[string]any,
+	ep string,
+	chainID string,
+	ctx map[string]any,
+	key *apikey.Key,
+) (*handlers.GetPaymasterStubDataResponse, error) {
+	l := c.logger.WithName("pm_getPaymasterStubData")
+	if err := c.checkChainID(chainID); err != nil {
+		l.Error(err, "pm_getPaymasterStubData error")
+		return nil, err
+	}
+	req, l, err := c.parseRequest(withDefaults(op, stubDefaults), ep, erc7677Context(ctx), key, l)
+	if err != nil {
+		l.Error(err, "pm_getPaymasterStubData error")
+		return nil, err
+	}
+
+	var res *handlers.GetPaymasterStubDataResponse
+	switch req.ctxType {
+	case "payg":
+		res, err = c.paygHandler.GetPaymasterStubData(req.pm, req.val, req.packedOp != nil)
+	case "erc20":
+		res, err = c.erc20Handler.GetPaymasterStubData(req.pm, req.token, req.val, req.packedOp != nil)
+	}
+	if err != nil {
+		l.Error(err, "pm_getPaymasterStubData error")
+		return nil, err
+	}
+
+	l.Info("pm_getPaymasterStubData ok")
+	return res, nil
+}
+
+// GetPaymasterData implements pm_getPaymasterData from ERC-7677. It returns signed paymaster fields for an
+// op that already has its final gas values. The op is checked against policies and budgets in the same way
+// as pm_sponsorUserOperation.
+func (c *Client) GetPaymasterData(
+	op map[string]any,
+	ep string,
+	chainID string,
+	ctx map[string]any,
+	key *apikey.Key,
+) (*handlers.GetPaymasterDataResponse, error) {
+	l := c.logger.WithName("pm_getPaymasterData")
+	if err := c.checkChainID(chainID); err != nil {
+		l.Error(err, "pm_getPaymasterData error")
+		return nil, err
+	}
+	defaults := map[string]any{"paymasterAndData": "0x", "signature": "0x"}
+	pmCtx := erc7677Context(ctx)
+	if pm, ok := paymasterFromOp(op); ok {
+		pmCtx = withDefaults(pmCtx, map[string]any{"paymaster": pm})
+	}
+	req, l, err := c.parseRequest(withDefaults(op, defaults), ep, pmCtx, key, l)
+	if err != nil {
+		l.Error(err, "pm_getPaymasterData error")
+		return nil, err
+	}
+	if err := c.callWebhook(req, pmCtx, l); err != nil {
+		l.Error(err, "pm_getPaymasterData error")
+		return nil, err
+	}
+
+	res, err := c.signAndValidate(req, func() (*handlers.SponsorUserOperationResponse, error) {
+		switch req.ctxType {
+		case "payg":
+			if req.packedOp != nil {
+				return c.paygHandler.GetPaymasterDataV07(req.packedOp, req.pm, req.pol, req.val)
+			}
+			return c.paygHandler.GetPaymasterData(req.userOp, req.pm, req.pol, req.val)
+		case "erc20":
+			if req.packedOp != nil {
+				return c.erc20Handler.GetPaymasterDataV07(req.packedOp, req.pm, req.token, req.pol, req.val)
+			}
+			return c.erc20Handler.GetPaymasterData(req.userOp, req.pm, req.token, req.pol, req.val)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		l.Error(err, "pm_getPaymasterData error")
+		return nil, err
+	}
+	if err := c.record(req, res, key); err != nil {
+		l.Error(err, "pm_getPaymasterData error")
+		return nil, err
+	}
+
+	l.Info("pm_getPaymasterData ok")
+	return handlers.NewPaymasterDataResponse(res), nil
+}
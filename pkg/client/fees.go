@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+
+	"github.com/stackup-wallet/stackup-paymaster/pkg/fees"
+)
+
+// suggestFees replaces the fees on the op of the request if the context asked for it or if maxFeePerGas is
+// zero. This is done before gas estimation since preVerificationGas on some networks depends on the fees.
+// It returns nil if fee suggestions are disabled or not required.
+func (c *Client) suggestFees(req *request) (*fees.Fees, error) {
+	if !req.suggestFees && req.maxFeePerGas().Sign() != 0 {
+		return nil, nil
+	}
+
+	f, err := c.fees.Suggest(context.Background())
+	if err != nil || f == nil {
+		return nil, err
+	}
+
+	if req.packedOp != nil {
+		op := *req.packedOp
+		op.MaxFeePerGas = f.MaxFeePerGas
+		op.MaxPriorityFeePerGas = f.MaxPriorityFeePerGas
+		req.packedOp = &op
+	} else {
+		op := *req.userOp
+		op.MaxFeePerGas = f.MaxFeePerGas
+		op.MaxPriorityFeePerGas = f.MaxPriorityFeePerGas
+		req.userOp = &op
+	}
+	return f, nil
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// erc7677ChainIDIndex is the position of the chain ID in the params of all ERC-7677 methods.
+	erc7677ChainIDIndex = 2
+
+	// erc7677ContextIndex is the position of the context in the params of all ERC-7677 methods.
+	erc7677ContextIndex = 3
+
+	erc7677ChainIDKey = "erc7677-chain-id"
+)
+
+// ERC7677Middleware returns a Gin middleware that replaces a null context param in ERC-7677 requests with an
+// empty object. Wallets will commonly send a null context when none is given, which would otherwise be
+// rejected by the JSON-RPC controller. The chainId param is also saved so that the request can be routed to
+// the correct chain.
+func ERC7677Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil {
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		data := make(map[string]any)
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&data); err != nil {
+			return
+		}
+		method, _ := data["method"].(string)
+		params, _ := data["params"].([]any)
+		if method != "pm_getPaymasterStubData" && method != "pm_getPaymasterData" {
+			return
+		}
+		if len(params) > erc7677ChainIDIndex {
+			if chainID, ok := params[erc7677ChainIDIndex].(string); ok {
+				c.Set(erc7677ChainIDKey, chainID)
+			}
+		}
+		if len(params) <= erc7677ContextIndex || params[erc7677ContextIndex] != nil {
+			return
+		}
+
+		params[erc7677ContextIndex] = map[string]any{}
+		if body, err = json.Marshal(data); err == nil {
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/apikey"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+var ether = big.NewInt(1e18)
+
+// declined returns the JSON-RPC error code if err is a reason for the paymaster to not sponsor an op, as
+// opposed to a failure to process the request.
+func declined(err error) (int, bool) {
+	var e *rpcerrors.Error
+	if !errors.As(err, &e) {
+		return 0, false
+	}
+	switch e.Code() {
+	case rpcerrors.CodePolicyRejected, rpcerrors.CodeBudgetExceeded, rpcerrors.CodeInsufficientDeposit:
+		return e.Code(), true
+	default:
+		return 0, false
+	}
+}
+
+// estimate returns the gas limits and max cost of the op for the sponsorship type of the request. The op is
+// returned as a v0.6 UserOperation in both cases so it can be evaluated against a policy.
+func (c *Client) estimate(req *request) (*handlers.QuoteResponse, *userop.UserOperation, *big.Int, error) {
+	var pmOp *userop.UserOperation
+	var pmOpV07 *packedop.UserOperation
+	var rate *big.Int
+	var err error
+	switch req.ctxType {
+	case "payg":
+		if req.packedOp != nil {
+			pmOpV07, err = c.paygHandler.EstimateV07(req.packedOp, req.ep, req.pm, req.pol, req.val)
+		} else {
+			pmOp, err = c.paygHandler.Estimate(req.userOp, req.ep, req.pm, req.pol, req.val)
+		}
+	case "erc20":
+		if req.packedOp != nil {
+			pmOpV07, rate, err = c.erc20Handler.EstimateV07(
+				req.packedOp,
+				req.ep,
+				req.pm,
+				req.token,
+				req.pol,
+				req.val,
+			)
+		} else {
+			pmOp, rate, err = c.erc20Handler.Estimate(req.userOp, req.ep, req.pm, req.token, req.pol, req.val)
+		}
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	res := &handlers.QuoteResponse{Sponsored: true, Paymaster: req.pm.Hex()}
+	var maxCost *big.Int
+	if pmOpV07 != nil {
+		res.PaymasterVerificationGasLimit = hexutil.EncodeBig(pmOpV07.PaymasterVerificationGasLimit)
+		res.PaymasterPostOpGasLimit = hexutil.EncodeBig(pmOpV07.PaymasterPostOpGasLimit)
+		maxCost = pmOpV07.GetMaxPrefund()
+		pmOp = pmOpV07.ToUserOperation()
+	} else {
+		maxCost = pmOp.GetMaxPrefund()
+	}
+	res.PreVerificationGas = hexutil.EncodeBig(pmOp.PreVerificationGas)
+	res.VerificationGasLimit = hexutil.EncodeBig(pmOp.VerificationGasLimit)
+	res.CallGasLimit = hexutil.EncodeBig(pmOp.CallGasLimit)
+	res.MaxFeePerGas = hexutil.EncodeBig(pmOp.MaxFeePerGas)
+	res.MaxPriorityFeePerGas = hexutil.EncodeBig(pmOp.MaxPriorityFeePerGas)
+	res.MaxCost = hexutil.EncodeBig(maxCost)
+
+	if rate != nil {
+		tokenCost := big.NewInt(0).Mul(maxCost, rate)
+		res.Token = req.token.Hex()
+		res.ExchangeRate = hexutil.EncodeBig(rate)
+		res.MaxTokenCost = hexutil.EncodeBig(tokenCost.Div(tokenCost, ether))
+	}
+	return res, pmOp, maxCost, nil
+}
+
+// Quote returns the gas limits and cost of sponsoring an op, and whether it would be sponsored. Ops can
+// omit gas and fee fields in the same way as pm_getPaymasterStubData. Nothing is signed, budgets are not
+// reserved, and policy webhooks are not called.
+func (c *Client) Quote(
+	op map[string]any,
+	ep string,
+	ctx map[string]any,
+	key *apikey.Key,
+) (*handlers.QuoteResponse, error) {
+	req, l, err := c.parseRequest(withDefaults(op, stubDefaults), ep, ctx, key, c.logger.WithName("pm_quote"))
+	if err != nil {
+		l.Error(err, "pm_quote error")
+		return nil, err
+	}
+	if _, err := c.suggestFees(req); err != nil {
+		l.Error(err, "pm_quote error")
+		return nil, err
+	}
+	if maxFee := req.maxFeePerGas(); maxFee.Sign() == 0 {
+		err := rpcerrors.InvalidParams(errors.New("maxFeePerGas: required if fees are not suggested"))
+		l.Error(err, "pm_quote error")
+		return nil, err
+	}
+
+	res, pmOp, maxCost, err := c.estimate(req)
+	if err != nil {
+		l.Error(err, "pm_quote error")
+		return nil, err
+	}
+
+	err = req.pol.Evaluate(pmOp, maxCost)
+	if err == nil {
+		err = c.deposits.Check(req.ep, req.pm, maxCost)
+	}
+	if err == nil {
+		err = c.budgets.Check(pmOp.Sender, maxCost, key.BudgetScopes()...)
+	}
+	if code, ok := declined(err); ok {
+		res.Sponsored = false
+		res.Reason = err.Error()
+		res.ReasonCode = code
+	} else if err != nil {
+		l.Error(err, "pm_quote error")
+		return nil, err
+	}
+
+	l.WithValues("sponsored", res.Sponsored).Info("pm_quote ok")
+	return res, nil
+}
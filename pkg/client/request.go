@@ -0,0 +1,253 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-logr/logr"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/apikey"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/calldata"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/validation"
+)
+
+// request holds the decoded and validated inputs that are common to all sponsorship methods. Only one of
+// userOp or packedOp is set depending on the EntryPoint version.
+type request struct {
+	ep       common.Address
+	pm       common.Address
+	userOp   *userop.UserOperation
+	packedOp *packedop.UserOperation
+	ctxType  string
+	token    common.Address
+	pol      *policy.Policy
+	val      *handlers.Validity
+
+	// suggestFees is true if the context asked for the paymaster to set the op fees.
+	suggestFees bool
+}
+
+// callData returns the callData of the op regardless of EntryPoint version.
+func (r *request) callData() []byte {
+	if r.packedOp != nil {
+		return r.packedOp.CallData
+	}
+	return r.userOp.CallData
+}
+
+// sender returns the sender of the op regardless of EntryPoint version.
+func (r *request) sender() common.Address {
+	if r.packedOp != nil {
+		return r.packedOp.Sender
+	}
+	return r.userOp.Sender
+}
+
+// maxFeePerGas returns the maxFeePerGas of the op regardless of EntryPoint version.
+func (r *request) maxFeePerGas() *big.Int {
+	if r.packedOp != nil {
+		return r.packedOp.MaxFeePerGas
+	}
+	return r.userOp.MaxFeePerGas
+}
+
+// factory returns the factory of the op or the zero address if the account is already deployed.
+func (r *request) factory() common.Address {
+	if r.packedOp != nil {
+		return r.packedOp.Factory
+	}
+	return r.userOp.GetFactory()
+}
+
+// parseRequest decodes the raw RPC params into a request. The returned logger has all relevant values of
+// the request attached and should be used even if an error is returned.
+func (c *Client) parseRequest(
+	op map[string]any,
+	ep string,
+	ctx map[string]any,
+	key *apikey.Key,
+	l logr.Logger,
+) (*request, logr.Logger, error) {
+	var err error
+	if key != nil {
+		l = l.WithValues("api_key", key.Name)
+	}
+
+	epAddr := common.HexToAddress(ep)
+	pmAddrs, ok := c.ep2pms[epAddr]
+	if !ok || !key.AllowsEntryPoint(epAddr) {
+		return nil, l, rpcerrors.InvalidParams(errors.New("entryPoint: Implementation not supported"))
+	}
+	l = l.WithValues("entrypoint", epAddr.String()).
+		WithValues("paymasters", pmAddrs).
+		WithValues("chain_id", c.chainID.String())
+	req := &request{ep: epAddr}
+
+	if c.v07[epAddr] {
+		req.packedOp, err = packedop.New(op)
+	} else {
+		req.userOp, err = userop.New(op)
+	}
+	if err != nil {
+		return nil, l, rpcerrors.InvalidParams(fmt.Errorf("bad userOp: %s", err))
+	}
+	if err := c.access.Check(req.sender(), req.factory(), req.callData()); err != nil {
+		return nil, l, err
+	}
+	if calls, err := calldata.Decode(req.callData()); err == nil {
+		l = l.WithValues("calls", calls)
+	}
+
+	ct, err := handlers.NewContextType(ctx)
+	if err != nil {
+		return nil, l, rpcerrors.InvalidParams(fmt.Errorf("bad context: %s", err))
+	}
+	l = l.WithValues("type", ct.Type)
+	req.ctxType = ct.Type
+
+	switch ct.Type {
+	case "payg":
+	case "erc20":
+		erc20Ctx, err := handlers.NewERC20Context(ctx)
+		if err != nil {
+			return nil, l, rpcerrors.InvalidParams(fmt.Errorf("bad context: %s", err))
+		}
+		l = l.WithValues("token", erc20Ctx.Token.String())
+		req.token = erc20Ctx.Token
+	default:
+		return nil, l, rpcerrors.InvalidParams(fmt.Errorf("type: %s not recognized", ct.Type))
+	}
+
+	opts, err := handlers.NewContextOptions(ctx)
+	if err != nil {
+		return nil, l, rpcerrors.InvalidParams(fmt.Errorf("bad context: %s", err))
+	}
+	policyID, ok := key.PolicyID(opts.PolicyID)
+	if !ok {
+		return nil, l, policy.Reject(policyID, "policyId not allowed for api key")
+	}
+	req.pol, err = c.policies.Get(policyID)
+	if err != nil {
+		return nil, l, err
+	}
+	if req.pol != nil {
+		l = l.WithValues("policy_id", req.pol.ID)
+	}
+
+	req.suggestFees = opts.SuggestFees
+
+	req.pm, err = c.selectPaymaster(epAddr, pmAddrs, opts.Paymaster, req.pol)
+	if err != nil {
+		return nil, l, err
+	}
+	l = l.WithValues("paymaster", req.pm.Hex())
+
+	req.val, err = c.validity.NewValidity(opts, req.pol)
+	if err != nil {
+		return nil, l, rpcerrors.InvalidParams(fmt.Errorf("bad context: %s", err))
+	}
+	l = l.WithValues("valid_after", req.val.ValidAfter.String()).
+		WithValues("valid_until", req.val.ValidUntil.String())
+
+	return req, l, nil
+}
+
+// selectPaymaster returns the paymaster to sponsor with. A paymaster requested in the context is used as long
+// as it is configured for the EntryPoint and allowed by the policy. Otherwise one is picked by the selector.
+func (c *Client) selectPaymaster(
+	ep common.Address,
+	pms []common.Address,
+	requested common.Address,
+	pol *policy.Policy,
+) (common.Address, error) {
+	candidates := pol.FilterPaymasters(pms)
+	if len(candidates) == 0 {
+		return common.Address{}, policy.Reject(pol.ID, "no paymasters allowed")
+	}
+
+	if requested != (common.Address{}) {
+		for _, pm := range candidates {
+			if pm == requested {
+				return pm, nil
+			}
+		}
+		err := fmt.Errorf("paymaster: %s not supported", requested.Hex())
+		return common.Address{}, rpcerrors.InvalidParams(err)
+	}
+
+	return c.selector.Select(ep, candidates), nil
+}
+
+// validate checks the signed op of the response against the paymaster on chain before it is returned to the
+// client.
+func (c *Client) validate(req *request, res *handlers.SponsorUserOperationResponse) error {
+	if req.packedOp != nil {
+		pmOp, err := applyResponseV07(req.packedOp, res)
+		if err != nil {
+			return err
+		}
+		return c.validator.ValidateV07(pmOp, req.ep)
+	}
+
+	pmOp, err := applyResponse(req.userOp, res)
+	if err != nil {
+		return err
+	}
+	return c.validator.Validate(pmOp, req.ep)
+}
+
+// signAndValidate returns the response from sign once it passes validation. If the paymaster rejects the
+// signature, its verifier may have been rotated since the last refresh. In that case the verifier is
+// refreshed and the op is signed and validated once more.
+func (c *Client) signAndValidate(
+	req *request,
+	sign func() (*handlers.SponsorUserOperationResponse, error),
+) (*handlers.SponsorUserOperationResponse, error) {
+	res, err := sign()
+	if err != nil {
+		return nil, err
+	}
+	err = c.validate(req, res)
+	if err == nil {
+		return res, nil
+	} else if !errors.Is(err, validation.ErrSignatureFailed) {
+		return nil, err
+	}
+
+	if err := c.keys.Refresh([]common.Address{req.pm}); err != nil {
+		return nil, err
+	}
+	if res, err = sign(); err != nil {
+		return nil, err
+	}
+	if err := c.validate(req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// record calls recordSponsorship for the op that matches the EntryPoint version of the request.
+func (c *Client) record(
+	req *request,
+	res *handlers.SponsorUserOperationResponse,
+	key *apikey.Key,
+) error {
+	var s *sponsoredOp
+	var err error
+	if req.packedOp != nil {
+		s, err = newSponsoredOpV07(req.packedOp, req.ep, c.chainID, res)
+	} else {
+		s, err = newSponsoredOp(req.userOp, req.ep, c.chainID, res)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.recordSponsorship(s, req.ep, key, req.pol)
+}
@@ -1,23 +1,66 @@
 package client
 
-import "github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+import (
+	"github.com/stackup-wallet/stackup-paymaster/pkg/apikey"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/calldata"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+// optional_context is the type of an ERC-7677 context param. The prefix allows the param to be omitted from
+// the request.
+type optional_context map[string]any
 
 type RpcAdapter struct {
 	client *Client
+	key    *apikey.Key
 }
 
-func NewRpcAdapter(c *Client) *RpcAdapter {
+// NewRpcAdapter returns an RpcAdapter for a single request. The key is the authenticated API key of the
+// request or nil if authentication is not enabled.
+func NewRpcAdapter(c *Client, key *apikey.Key) *RpcAdapter {
 	return &RpcAdapter{
 		client: c,
+		key:    key,
 	}
 }
 
 func (r *RpcAdapter) Pm_accounts(ep string) ([]string, error) {
-	return r.client.Accounts(ep)
+	res, err := r.client.Accounts(ep, r.key)
+	return res, rpcerrors.Encode(err)
+}
+
+func (r *RpcAdapter) Pm_decodeCallData(callData string) ([]*calldata.Call, error) {
+	res, err := r.client.DecodeCallData(callData)
+	return res, rpcerrors.Encode(err)
 }
 
 func (r *RpcAdapter) Pm_sponsorUserOperation(op map[string]any,
 	ep string,
 	ctx map[string]any) (*handlers.SponsorUserOperationResponse, error) {
-	return r.client.SponsorUserOperation(op, ep, ctx)
+	res, err := r.client.SponsorUserOperation(op, ep, ctx, r.key)
+	return res, rpcerrors.Encode(err)
+}
+
+func (r *RpcAdapter) Pm_quote(op map[string]any,
+	ep string,
+	ctx map[string]any) (*handlers.QuoteResponse, error) {
+	res, err := r.client.Quote(op, ep, ctx, r.key)
+	return res, rpcerrors.Encode(err)
+}
+
+func (r *RpcAdapter) Pm_getPaymasterStubData(op map[string]any,
+	ep string,
+	chainID string,
+	ctx optional_context) (*handlers.GetPaymasterStubDataResponse, error) {
+	res, err := r.client.GetPaymasterStubData(op, ep, chainID, ctx, r.key)
+	return res, rpcerrors.Encode(err)
+}
+
+func (r *RpcAdapter) Pm_getPaymasterData(op map[string]any,
+	ep string,
+	chainID string,
+	ctx optional_context) (*handlers.GetPaymasterDataResponse, error) {
+	res, err := r.client.GetPaymasterData(op, ep, chainID, ctx, r.key)
+	return res, rpcerrors.Encode(err)
 }
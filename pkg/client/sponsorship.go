@@ -0,0 +1,168 @@
+package client
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/apikey"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/watcher"
+)
+
+// sponsoredOp holds the details of a signed op that are required for budgets and tracking, regardless of
+// the EntryPoint version.
+type sponsoredOp struct {
+	sender     common.Address
+	nonce      *big.Int
+	userOpHash common.Hash
+	maxCost    *big.Int
+	data       *contract.Data
+}
+
+// applyResponse returns a copy of the op with all fields from a sponsorship response.
+func applyResponse(
+	op *userop.UserOperation,
+	res *handlers.SponsorUserOperationResponse,
+) (*userop.UserOperation, error) {
+	data, err := op.ToMap()
+	if err != nil {
+		return nil, err
+	}
+	data["paymasterAndData"] = res.PaymasterAndData
+	data["preVerificationGas"] = res.PreVerificationGas
+	data["verificationGasLimit"] = res.VerificationGasLimit
+	data["callGasLimit"] = res.CallGasLimit
+	if res.MaxFeePerGas != "" {
+		data["maxFeePerGas"] = res.MaxFeePerGas
+		data["maxPriorityFeePerGas"] = res.MaxPriorityFeePerGas
+	}
+
+	return userop.New(data)
+}
+
+// applyResponseV07 is the EntryPoint v0.7 equivalent of applyResponse.
+func applyResponseV07(
+	op *packedop.UserOperation,
+	res *handlers.SponsorUserOperationResponse,
+) (*packedop.UserOperation, error) {
+	data, err := op.ToMap()
+	if err != nil {
+		return nil, err
+	}
+	data["paymaster"] = res.Paymaster
+	data["paymasterData"] = res.PaymasterData
+	data["paymasterVerificationGasLimit"] = res.PaymasterVerificationGasLimit
+	data["paymasterPostOpGasLimit"] = res.PaymasterPostOpGasLimit
+	data["preVerificationGas"] = res.PreVerificationGas
+	data["verificationGasLimit"] = res.VerificationGasLimit
+	data["callGasLimit"] = res.CallGasLimit
+	if res.MaxFeePerGas != "" {
+		data["maxFeePerGas"] = res.MaxFeePerGas
+		data["maxPriorityFeePerGas"] = res.MaxPriorityFeePerGas
+	}
+
+	return packedop.New(data)
+}
+
+func newSponsoredOp(
+	op *userop.UserOperation,
+	ep common.Address,
+	chainID *big.Int,
+	res *handlers.SponsorUserOperationResponse,
+) (*sponsoredOp, error) {
+	pmOp, err := applyResponse(op, res)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := contract.DecodePaymasterAndData(pmOp.PaymasterAndData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sponsoredOp{
+		sender:     pmOp.Sender,
+		nonce:      pmOp.Nonce,
+		userOpHash: pmOp.GetUserOpHash(ep, chainID),
+		maxCost:    pmOp.GetMaxPrefund(),
+		data:       data,
+	}, nil
+}
+
+func newSponsoredOpV07(
+	op *packedop.UserOperation,
+	ep common.Address,
+	chainID *big.Int,
+	res *handlers.SponsorUserOperationResponse,
+) (*sponsoredOp, error) {
+	pmOp, err := applyResponseV07(op, res)
+	if err != nil {
+		return nil, err
+	}
+	pmData, err := hexutil.Decode(res.PaymasterData)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := contract.DecodePaymasterAndData(append(pmOp.Paymaster.Bytes(), pmData...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sponsoredOp{
+		sender:     pmOp.Sender,
+		nonce:      pmOp.Nonce,
+		userOpHash: pmOp.GetUserOpHash(ep, chainID),
+		maxCost:    pmOp.GetMaxPrefund(),
+		data:       data,
+	}, nil
+}
+
+// recordSponsorship reserves the max cost of the sponsored op against all budgets that apply to the sender
+// and API key. It then tracks the op so that the reservation can be reconciled once it is seen on chain.
+// The op is rejected if the paymaster deposit can't cover its max cost.
+func (c *Client) recordSponsorship(
+	s *sponsoredOp,
+	ep common.Address,
+	key *apikey.Key,
+	pol *policy.Policy,
+) error {
+	if err := c.deposits.Check(ep, s.data.Paymaster, s.maxCost); err != nil {
+		return err
+	}
+
+	reservation, err := c.budgets.Reserve(s.sender, s.maxCost, key.BudgetScopes()...)
+	if err != nil {
+		return err
+	}
+
+	r := &watcher.Record{
+		UserOpHash:  s.userOpHash,
+		EntryPoint:  ep,
+		Paymaster:   s.data.Paymaster,
+		Sender:      s.sender,
+		Nonce:       s.nonce,
+		MaxCost:     s.maxCost,
+		ValidUntil:  s.data.ValidUntil.Int64(),
+		SignedAt:    time.Now().Unix(),
+		Reservation: reservation,
+	}
+	if key != nil {
+		r.APIKey = key.Name
+	}
+	if pol != nil {
+		r.PolicyID = pol.ID
+	}
+	if err := c.watcher.Track(r); err != nil {
+		if releaseErr := c.budgets.Release(reservation, reservation.Amount); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
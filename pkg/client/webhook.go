@@ -0,0 +1,34 @@
+package client
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/go-logr/logr"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/calldata"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
+)
+
+// callWebhook sends the op and context of the request to the webhook of its policy, if one is set. This is
+// done before any paymaster data is signed.
+func (c *Client) callWebhook(req *request, ctx map[string]any, l logr.Logger) error {
+	if req.pol == nil || req.pol.Webhook == nil {
+		return nil
+	}
+
+	whReq := &policy.WebhookRequest{
+		ChainID:    hexutil.EncodeBig(c.chainID),
+		EntryPoint: req.ep,
+		Context:    ctx,
+	}
+	if req.packedOp != nil {
+		whReq.Sender = req.packedOp.Sender
+		whReq.UserOperation = req.packedOp
+	} else {
+		whReq.Sender = req.userOp.Sender
+		whReq.UserOperation = req.userOp
+	}
+	if calls, err := calldata.Decode(req.callData()); err == nil {
+		whReq.Calls = calls
+	}
+
+	return req.pol.CallWebhook(whReq, l.WithName("webhook"))
+}
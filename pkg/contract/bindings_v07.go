@@ -0,0 +1,855 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contract
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// PackedUserOperation is an auto generated low-level Go binding around an user-defined struct.
+type PackedUserOperation struct {
+	Sender             common.Address
+	Nonce              *big.Int
+	InitCode           []byte
+	CallData           []byte
+	AccountGasLimits   [32]byte
+	PreVerificationGas *big.Int
+	GasFees            [32]byte
+	PaymasterAndData   []byte
+	Signature          []byte
+}
+
+// ContractV07MetaData contains all meta data concerning the ContractV07 contract.
+var ContractV07MetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"contractIEntryPoint\",\"name\":\"_entryPoint\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_owner\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"previousOwner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"newOwner\",\"type\":\"address\"}],\"name\":\"OwnershipTransferred\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"POST_OP_GAS\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint32\",\"name\":\"unstakeDelaySec\",\"type\":\"uint32\"}],\"name\":\"addStake\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"deposit\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"entryPoint\",\"outputs\":[{\"internalType\":\"contractIEntryPoint\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getDeposit\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"nonce\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"initCode\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"callData\",\"type\":\"bytes\"},{\"internalType\":\"bytes32\",\"name\":\"accountGasLimits\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"preVerificationGas\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"gasFees\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"paymasterAndData\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"signature\",\"type\":\"bytes\"}],\"internalType\":\"structPackedUserOperation\",\"name\":\"userOp\",\"type\":\"tuple\"},{\"internalType\":\"uint48\",\"name\":\"validUntil\",\"type\":\"uint48\"},{\"internalType\":\"uint48\",\"name\":\"validAfter\",\"type\":\"uint48\"},{\"internalType\":\"address\",\"name\":\"erc20Token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"exchangeRate\",\"type\":\"uint256\"}],\"name\":\"getHash\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"paymasterAndData\",\"type\":\"bytes\"}],\"name\":\"parsePaymasterAndData\",\"outputs\":[{\"internalType\":\"uint48\",\"name\":\"validUntil\",\"type\":\"uint48\"},{\"internalType\":\"uint48\",\"name\":\"validAfter\",\"type\":\"uint48\"},{\"internalType\":\"address\",\"name\":\"erc20Token\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"exchangeRate\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"signature\",\"type\":\"bytes\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"enumIPaymaster.PostOpMode\",\"name\":\"mode\",\"type\":\"uint8\"},{\"internalType\":\"bytes\",\"name\":\"context\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"actualGasCost\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"actualUserOpFeePerGas\",\"type\":\"uint256\"}],\"name\":\"postOp\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"renounceOwnership\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_vault\",\"type\":\"address\"}],\"name\":\"setVault\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_verifier\",\"type\":\"address\"}],\"name\":\"setVerifier\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"newOwner\",\"type\":\"address\"}],\"name\":\"transferOwnership\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"unlockStake\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"nonce\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"initCode\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"callData\",\"type\":\"bytes\"},{\"internalType\":\"bytes32\",\"name\":\"accountGasLimits\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"preVerificationGas\",\"type\":\"uint256\"},{\"internalType\":\"bytes32\",\"name\":\"gasFees\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"paymasterAndData\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"signature\",\"type\":\"bytes\"}],\"internalType\":\"structPackedUserOperation\",\"name\":\"userOp\",\"type\":\"tuple\"},{\"internalType\":\"bytes32\",\"name\":\"userOpHash\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"maxCost\",\"type\":\"uint256\"}],\"name\":\"validatePaymasterUserOp\",\"outputs\":[{\"internalType\":\"bytes\",\"name\":\"context\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"validationData\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"vault\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"verifier\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"addresspayable\",\"name\":\"withdrawAddress\",\"type\":\"address\"}],\"name\":\"withdrawStake\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"addresspayable\",\"name\":\"withdrawAddress\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"withdrawTo\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+}
+
+// ContractV07ABI is the input ABI used to generate the binding from.
+// Deprecated: Use ContractV07MetaData.ABI instead.
+var ContractV07ABI = ContractV07MetaData.ABI
+
+// ContractV07 is an auto generated Go binding around an Ethereum contract.
+type ContractV07 struct {
+	ContractV07Caller     // Read-only binding to the contract
+	ContractV07Transactor // Write-only binding to the contract
+	ContractV07Filterer   // Log filterer for contract events
+}
+
+// ContractV07Caller is an auto generated read-only Go binding around an Ethereum contract.
+type ContractV07Caller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ContractV07Transactor is an auto generated write-only Go binding around an Ethereum contract.
+type ContractV07Transactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ContractV07Filterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type ContractV07Filterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ContractV07Session is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type ContractV07Session struct {
+	Contract     *ContractV07      // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// ContractV07CallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type ContractV07CallerSession struct {
+	Contract *ContractV07Caller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts      // Call options to use throughout this session
+}
+
+// ContractV07TransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type ContractV07TransactorSession struct {
+	Contract     *ContractV07Transactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts      // Transaction auth options to use throughout this session
+}
+
+// ContractV07Raw is an auto generated low-level Go binding around an Ethereum contract.
+type ContractV07Raw struct {
+	Contract *ContractV07 // Generic contract binding to access the raw methods on
+}
+
+// ContractV07CallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type ContractV07CallerRaw struct {
+	Contract *ContractV07Caller // Generic read-only contract binding to access the raw methods on
+}
+
+// ContractV07TransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type ContractV07TransactorRaw struct {
+	Contract *ContractV07Transactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewContractV07 creates a new instance of ContractV07, bound to a specific deployed contract.
+func NewContractV07(address common.Address, backend bind.ContractBackend) (*ContractV07, error) {
+	contract, err := bindContractV07(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractV07{ContractV07Caller: ContractV07Caller{contract: contract}, ContractV07Transactor: ContractV07Transactor{contract: contract}, ContractV07Filterer: ContractV07Filterer{contract: contract}}, nil
+}
+
+// NewContractV07Caller creates a new read-only instance of ContractV07, bound to a specific deployed contract.
+func NewContractV07Caller(address common.Address, caller bind.ContractCaller) (*ContractV07Caller, error) {
+	contract, err := bindContractV07(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractV07Caller{contract: contract}, nil
+}
+
+// NewContractV07Transactor creates a new write-only instance of ContractV07, bound to a specific deployed contract.
+func NewContractV07Transactor(address common.Address, transactor bind.ContractTransactor) (*ContractV07Transactor, error) {
+	contract, err := bindContractV07(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractV07Transactor{contract: contract}, nil
+}
+
+// NewContractV07Filterer creates a new log filterer instance of ContractV07, bound to a specific deployed contract.
+func NewContractV07Filterer(address common.Address, filterer bind.ContractFilterer) (*ContractV07Filterer, error) {
+	contract, err := bindContractV07(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractV07Filterer{contract: contract}, nil
+}
+
+// bindContractV07 binds a generic wrapper to an already deployed contract.
+func bindContractV07(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := ContractV07MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ContractV07 *ContractV07Raw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ContractV07.Contract.ContractV07Caller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ContractV07 *ContractV07Raw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ContractV07.Contract.ContractV07Transactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ContractV07 *ContractV07Raw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ContractV07.Contract.ContractV07Transactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ContractV07 *ContractV07CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ContractV07.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ContractV07 *ContractV07TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ContractV07.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ContractV07 *ContractV07TransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ContractV07.Contract.contract.Transact(opts, method, params...)
+}
+
+// POSTOPGAS is a free data retrieval call binding the contract method 0xb8202d8f.
+//
+// Solidity: function POST_OP_GAS() view returns(uint256)
+func (_ContractV07 *ContractV07Caller) POSTOPGAS(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _ContractV07.contract.Call(opts, &out, "POST_OP_GAS")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// POSTOPGAS is a free data retrieval call binding the contract method 0xb8202d8f.
+//
+// Solidity: function POST_OP_GAS() view returns(uint256)
+func (_ContractV07 *ContractV07Session) POSTOPGAS() (*big.Int, error) {
+	return _ContractV07.Contract.POSTOPGAS(&_ContractV07.CallOpts)
+}
+
+// POSTOPGAS is a free data retrieval call binding the contract method 0xb8202d8f.
+//
+// Solidity: function POST_OP_GAS() view returns(uint256)
+func (_ContractV07 *ContractV07CallerSession) POSTOPGAS() (*big.Int, error) {
+	return _ContractV07.Contract.POSTOPGAS(&_ContractV07.CallOpts)
+}
+
+// EntryPoint is a free data retrieval call binding the contract method 0xb0d691fe.
+//
+// Solidity: function entryPoint() view returns(address)
+func (_ContractV07 *ContractV07Caller) EntryPoint(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _ContractV07.contract.Call(opts, &out, "entryPoint")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// EntryPoint is a free data retrieval call binding the contract method 0xb0d691fe.
+//
+// Solidity: function entryPoint() view returns(address)
+func (_ContractV07 *ContractV07Session) EntryPoint() (common.Address, error) {
+	return _ContractV07.Contract.EntryPoint(&_ContractV07.CallOpts)
+}
+
+// EntryPoint is a free data retrieval call binding the contract method 0xb0d691fe.
+//
+// Solidity: function entryPoint() view returns(address)
+func (_ContractV07 *ContractV07CallerSession) EntryPoint() (common.Address, error) {
+	return _ContractV07.Contract.EntryPoint(&_ContractV07.CallOpts)
+}
+
+// GetDeposit is a free data retrieval call binding the contract method 0xc399ec88.
+//
+// Solidity: function getDeposit() view returns(uint256)
+func (_ContractV07 *ContractV07Caller) GetDeposit(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _ContractV07.contract.Call(opts, &out, "getDeposit")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetDeposit is a free data retrieval call binding the contract method 0xc399ec88.
+//
+// Solidity: function getDeposit() view returns(uint256)
+func (_ContractV07 *ContractV07Session) GetDeposit() (*big.Int, error) {
+	return _ContractV07.Contract.GetDeposit(&_ContractV07.CallOpts)
+}
+
+// GetDeposit is a free data retrieval call binding the contract method 0xc399ec88.
+//
+// Solidity: function getDeposit() view returns(uint256)
+func (_ContractV07 *ContractV07CallerSession) GetDeposit() (*big.Int, error) {
+	return _ContractV07.Contract.GetDeposit(&_ContractV07.CallOpts)
+}
+
+// GetHash is a free data retrieval call binding the contract method 0xe38e46f2.
+//
+// Solidity: function getHash((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes) userOp, uint48 validUntil, uint48 validAfter, address erc20Token, uint256 exchangeRate) view returns(bytes32)
+func (_ContractV07 *ContractV07Caller) GetHash(opts *bind.CallOpts, userOp PackedUserOperation, validUntil *big.Int, validAfter *big.Int, erc20Token common.Address, exchangeRate *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _ContractV07.contract.Call(opts, &out, "getHash", userOp, validUntil, validAfter, erc20Token, exchangeRate)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// GetHash is a free data retrieval call binding the contract method 0xe38e46f2.
+//
+// Solidity: function getHash((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes) userOp, uint48 validUntil, uint48 validAfter, address erc20Token, uint256 exchangeRate) view returns(bytes32)
+func (_ContractV07 *ContractV07Session) GetHash(userOp PackedUserOperation, validUntil *big.Int, validAfter *big.Int, erc20Token common.Address, exchangeRate *big.Int) ([32]byte, error) {
+	return _ContractV07.Contract.GetHash(&_ContractV07.CallOpts, userOp, validUntil, validAfter, erc20Token, exchangeRate)
+}
+
+// GetHash is a free data retrieval call binding the contract method 0xe38e46f2.
+//
+// Solidity: function getHash((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes) userOp, uint48 validUntil, uint48 validAfter, address erc20Token, uint256 exchangeRate) view returns(bytes32)
+func (_ContractV07 *ContractV07CallerSession) GetHash(userOp PackedUserOperation, validUntil *big.Int, validAfter *big.Int, erc20Token common.Address, exchangeRate *big.Int) ([32]byte, error) {
+	return _ContractV07.Contract.GetHash(&_ContractV07.CallOpts, userOp, validUntil, validAfter, erc20Token, exchangeRate)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_ContractV07 *ContractV07Caller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _ContractV07.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_ContractV07 *ContractV07Session) Owner() (common.Address, error) {
+	return _ContractV07.Contract.Owner(&_ContractV07.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_ContractV07 *ContractV07CallerSession) Owner() (common.Address, error) {
+	return _ContractV07.Contract.Owner(&_ContractV07.CallOpts)
+}
+
+// ParsePaymasterAndData is a free data retrieval call binding the contract method 0x94d4ad60.
+//
+// Solidity: function parsePaymasterAndData(bytes paymasterAndData) pure returns(uint48 validUntil, uint48 validAfter, address erc20Token, uint256 exchangeRate, bytes signature)
+func (_ContractV07 *ContractV07Caller) ParsePaymasterAndData(opts *bind.CallOpts, paymasterAndData []byte) (struct {
+	ValidUntil   *big.Int
+	ValidAfter   *big.Int
+	Erc20Token   common.Address
+	ExchangeRate *big.Int
+	Signature    []byte
+}, error) {
+	var out []interface{}
+	err := _ContractV07.contract.Call(opts, &out, "parsePaymasterAndData", paymasterAndData)
+
+	outstruct := new(struct {
+		ValidUntil   *big.Int
+		ValidAfter   *big.Int
+		Erc20Token   common.Address
+		ExchangeRate *big.Int
+		Signature    []byte
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.ValidUntil = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.ValidAfter = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.Erc20Token = *abi.ConvertType(out[2], new(common.Address)).(*common.Address)
+	outstruct.ExchangeRate = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.Signature = *abi.ConvertType(out[4], new([]byte)).(*[]byte)
+
+	return *outstruct, err
+
+}
+
+// ParsePaymasterAndData is a free data retrieval call binding the contract method 0x94d4ad60.
+//
+// Solidity: function parsePaymasterAndData(bytes paymasterAndData) pure returns(uint48 validUntil, uint48 validAfter, address erc20Token, uint256 exchangeRate, bytes signature)
+func (_ContractV07 *ContractV07Session) ParsePaymasterAndData(paymasterAndData []byte) (struct {
+	ValidUntil   *big.Int
+	ValidAfter   *big.Int
+	Erc20Token   common.Address
+	ExchangeRate *big.Int
+	Signature    []byte
+}, error) {
+	return _ContractV07.Contract.ParsePaymasterAndData(&_ContractV07.CallOpts, paymasterAndData)
+}
+
+// ParsePaymasterAndData is a free data retrieval call binding the contract method 0x94d4ad60.
+//
+// Solidity: function parsePaymasterAndData(bytes paymasterAndData) pure returns(uint48 validUntil, uint48 validAfter, address erc20Token, uint256 exchangeRate, bytes signature)
+func (_ContractV07 *ContractV07CallerSession) ParsePaymasterAndData(paymasterAndData []byte) (struct {
+	ValidUntil   *big.Int
+	ValidAfter   *big.Int
+	Erc20Token   common.Address
+	ExchangeRate *big.Int
+	Signature    []byte
+}, error) {
+	return _ContractV07.Contract.ParsePaymasterAndData(&_ContractV07.CallOpts, paymasterAndData)
+}
+
+// Vault is a free data retrieval call binding the contract method 0xfbfa77cf.
+//
+// Solidity: function vault() view returns(address)
+func (_ContractV07 *ContractV07Caller) Vault(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _ContractV07.contract.Call(opts, &out, "vault")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Vault is a free data retrieval call binding the contract method 0xfbfa77cf.
+//
+// Solidity: function vault() view returns(address)
+func (_ContractV07 *ContractV07Session) Vault() (common.Address, error) {
+	return _ContractV07.Contract.Vault(&_ContractV07.CallOpts)
+}
+
+// Vault is a free data retrieval call binding the contract method 0xfbfa77cf.
+//
+// Solidity: function vault() view returns(address)
+func (_ContractV07 *ContractV07CallerSession) Vault() (common.Address, error) {
+	return _ContractV07.Contract.Vault(&_ContractV07.CallOpts)
+}
+
+// Verifier is a free data retrieval call binding the contract method 0x2b7ac3f3.
+//
+// Solidity: function verifier() view returns(address)
+func (_ContractV07 *ContractV07Caller) Verifier(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _ContractV07.contract.Call(opts, &out, "verifier")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Verifier is a free data retrieval call binding the contract method 0x2b7ac3f3.
+//
+// Solidity: function verifier() view returns(address)
+func (_ContractV07 *ContractV07Session) Verifier() (common.Address, error) {
+	return _ContractV07.Contract.Verifier(&_ContractV07.CallOpts)
+}
+
+// Verifier is a free data retrieval call binding the contract method 0x2b7ac3f3.
+//
+// Solidity: function verifier() view returns(address)
+func (_ContractV07 *ContractV07CallerSession) Verifier() (common.Address, error) {
+	return _ContractV07.Contract.Verifier(&_ContractV07.CallOpts)
+}
+
+// AddStake is a paid mutator transaction binding the contract method 0x0396cb60.
+//
+// Solidity: function addStake(uint32 unstakeDelaySec) payable returns()
+func (_ContractV07 *ContractV07Transactor) AddStake(opts *bind.TransactOpts, unstakeDelaySec uint32) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "addStake", unstakeDelaySec)
+}
+
+// AddStake is a paid mutator transaction binding the contract method 0x0396cb60.
+//
+// Solidity: function addStake(uint32 unstakeDelaySec) payable returns()
+func (_ContractV07 *ContractV07Session) AddStake(unstakeDelaySec uint32) (*types.Transaction, error) {
+	return _ContractV07.Contract.AddStake(&_ContractV07.TransactOpts, unstakeDelaySec)
+}
+
+// AddStake is a paid mutator transaction binding the contract method 0x0396cb60.
+//
+// Solidity: function addStake(uint32 unstakeDelaySec) payable returns()
+func (_ContractV07 *ContractV07TransactorSession) AddStake(unstakeDelaySec uint32) (*types.Transaction, error) {
+	return _ContractV07.Contract.AddStake(&_ContractV07.TransactOpts, unstakeDelaySec)
+}
+
+// Deposit is a paid mutator transaction binding the contract method 0xd0e30db0.
+//
+// Solidity: function deposit() payable returns()
+func (_ContractV07 *ContractV07Transactor) Deposit(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "deposit")
+}
+
+// Deposit is a paid mutator transaction binding the contract method 0xd0e30db0.
+//
+// Solidity: function deposit() payable returns()
+func (_ContractV07 *ContractV07Session) Deposit() (*types.Transaction, error) {
+	return _ContractV07.Contract.Deposit(&_ContractV07.TransactOpts)
+}
+
+// Deposit is a paid mutator transaction binding the contract method 0xd0e30db0.
+//
+// Solidity: function deposit() payable returns()
+func (_ContractV07 *ContractV07TransactorSession) Deposit() (*types.Transaction, error) {
+	return _ContractV07.Contract.Deposit(&_ContractV07.TransactOpts)
+}
+
+// PostOp is a paid mutator transaction binding the contract method 0x7c627b21.
+//
+// Solidity: function postOp(uint8 mode, bytes context, uint256 actualGasCost, uint256 actualUserOpFeePerGas) returns()
+func (_ContractV07 *ContractV07Transactor) PostOp(opts *bind.TransactOpts, mode uint8, context []byte, actualGasCost *big.Int, actualUserOpFeePerGas *big.Int) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "postOp", mode, context, actualGasCost, actualUserOpFeePerGas)
+}
+
+// PostOp is a paid mutator transaction binding the contract method 0x7c627b21.
+//
+// Solidity: function postOp(uint8 mode, bytes context, uint256 actualGasCost, uint256 actualUserOpFeePerGas) returns()
+func (_ContractV07 *ContractV07Session) PostOp(mode uint8, context []byte, actualGasCost *big.Int, actualUserOpFeePerGas *big.Int) (*types.Transaction, error) {
+	return _ContractV07.Contract.PostOp(&_ContractV07.TransactOpts, mode, context, actualGasCost, actualUserOpFeePerGas)
+}
+
+// PostOp is a paid mutator transaction binding the contract method 0x7c627b21.
+//
+// Solidity: function postOp(uint8 mode, bytes context, uint256 actualGasCost, uint256 actualUserOpFeePerGas) returns()
+func (_ContractV07 *ContractV07TransactorSession) PostOp(mode uint8, context []byte, actualGasCost *big.Int, actualUserOpFeePerGas *big.Int) (*types.Transaction, error) {
+	return _ContractV07.Contract.PostOp(&_ContractV07.TransactOpts, mode, context, actualGasCost, actualUserOpFeePerGas)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_ContractV07 *ContractV07Transactor) RenounceOwnership(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "renounceOwnership")
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_ContractV07 *ContractV07Session) RenounceOwnership() (*types.Transaction, error) {
+	return _ContractV07.Contract.RenounceOwnership(&_ContractV07.TransactOpts)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_ContractV07 *ContractV07TransactorSession) RenounceOwnership() (*types.Transaction, error) {
+	return _ContractV07.Contract.RenounceOwnership(&_ContractV07.TransactOpts)
+}
+
+// SetVault is a paid mutator transaction binding the contract method 0x6817031b.
+//
+// Solidity: function setVault(address _vault) returns()
+func (_ContractV07 *ContractV07Transactor) SetVault(opts *bind.TransactOpts, _vault common.Address) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "setVault", _vault)
+}
+
+// SetVault is a paid mutator transaction binding the contract method 0x6817031b.
+//
+// Solidity: function setVault(address _vault) returns()
+func (_ContractV07 *ContractV07Session) SetVault(_vault common.Address) (*types.Transaction, error) {
+	return _ContractV07.Contract.SetVault(&_ContractV07.TransactOpts, _vault)
+}
+
+// SetVault is a paid mutator transaction binding the contract method 0x6817031b.
+//
+// Solidity: function setVault(address _vault) returns()
+func (_ContractV07 *ContractV07TransactorSession) SetVault(_vault common.Address) (*types.Transaction, error) {
+	return _ContractV07.Contract.SetVault(&_ContractV07.TransactOpts, _vault)
+}
+
+// SetVerifier is a paid mutator transaction binding the contract method 0x5437988d.
+//
+// Solidity: function setVerifier(address _verifier) returns()
+func (_ContractV07 *ContractV07Transactor) SetVerifier(opts *bind.TransactOpts, _verifier common.Address) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "setVerifier", _verifier)
+}
+
+// SetVerifier is a paid mutator transaction binding the contract method 0x5437988d.
+//
+// Solidity: function setVerifier(address _verifier) returns()
+func (_ContractV07 *ContractV07Session) SetVerifier(_verifier common.Address) (*types.Transaction, error) {
+	return _ContractV07.Contract.SetVerifier(&_ContractV07.TransactOpts, _verifier)
+}
+
+// SetVerifier is a paid mutator transaction binding the contract method 0x5437988d.
+//
+// Solidity: function setVerifier(address _verifier) returns()
+func (_ContractV07 *ContractV07TransactorSession) SetVerifier(_verifier common.Address) (*types.Transaction, error) {
+	return _ContractV07.Contract.SetVerifier(&_ContractV07.TransactOpts, _verifier)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_ContractV07 *ContractV07Transactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "transferOwnership", newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_ContractV07 *ContractV07Session) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _ContractV07.Contract.TransferOwnership(&_ContractV07.TransactOpts, newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_ContractV07 *ContractV07TransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _ContractV07.Contract.TransferOwnership(&_ContractV07.TransactOpts, newOwner)
+}
+
+// UnlockStake is a paid mutator transaction binding the contract method 0xbb9fe6bf.
+//
+// Solidity: function unlockStake() returns()
+func (_ContractV07 *ContractV07Transactor) UnlockStake(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "unlockStake")
+}
+
+// UnlockStake is a paid mutator transaction binding the contract method 0xbb9fe6bf.
+//
+// Solidity: function unlockStake() returns()
+func (_ContractV07 *ContractV07Session) UnlockStake() (*types.Transaction, error) {
+	return _ContractV07.Contract.UnlockStake(&_ContractV07.TransactOpts)
+}
+
+// UnlockStake is a paid mutator transaction binding the contract method 0xbb9fe6bf.
+//
+// Solidity: function unlockStake() returns()
+func (_ContractV07 *ContractV07TransactorSession) UnlockStake() (*types.Transaction, error) {
+	return _ContractV07.Contract.UnlockStake(&_ContractV07.TransactOpts)
+}
+
+// ValidatePaymasterUserOp is a paid mutator transaction binding the contract method 0x52b7512c.
+//
+// Solidity: function validatePaymasterUserOp((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes) userOp, bytes32 userOpHash, uint256 maxCost) returns(bytes context, uint256 validationData)
+func (_ContractV07 *ContractV07Transactor) ValidatePaymasterUserOp(opts *bind.TransactOpts, userOp PackedUserOperation, userOpHash [32]byte, maxCost *big.Int) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "validatePaymasterUserOp", userOp, userOpHash, maxCost)
+}
+
+// ValidatePaymasterUserOp is a paid mutator transaction binding the contract method 0x52b7512c.
+//
+// Solidity: function validatePaymasterUserOp((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes) userOp, bytes32 userOpHash, uint256 maxCost) returns(bytes context, uint256 validationData)
+func (_ContractV07 *ContractV07Session) ValidatePaymasterUserOp(userOp PackedUserOperation, userOpHash [32]byte, maxCost *big.Int) (*types.Transaction, error) {
+	return _ContractV07.Contract.ValidatePaymasterUserOp(&_ContractV07.TransactOpts, userOp, userOpHash, maxCost)
+}
+
+// ValidatePaymasterUserOp is a paid mutator transaction binding the contract method 0x52b7512c.
+//
+// Solidity: function validatePaymasterUserOp((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes) userOp, bytes32 userOpHash, uint256 maxCost) returns(bytes context, uint256 validationData)
+func (_ContractV07 *ContractV07TransactorSession) ValidatePaymasterUserOp(userOp PackedUserOperation, userOpHash [32]byte, maxCost *big.Int) (*types.Transaction, error) {
+	return _ContractV07.Contract.ValidatePaymasterUserOp(&_ContractV07.TransactOpts, userOp, userOpHash, maxCost)
+}
+
+// WithdrawStake is a paid mutator transaction binding the contract method 0xc23a5cea.
+//
+// Solidity: function withdrawStake(address withdrawAddress) returns()
+func (_ContractV07 *ContractV07Transactor) WithdrawStake(opts *bind.TransactOpts, withdrawAddress common.Address) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "withdrawStake", withdrawAddress)
+}
+
+// WithdrawStake is a paid mutator transaction binding the contract method 0xc23a5cea.
+//
+// Solidity: function withdrawStake(address withdrawAddress) returns()
+func (_ContractV07 *ContractV07Session) WithdrawStake(withdrawAddress common.Address) (*types.Transaction, error) {
+	return _ContractV07.Contract.WithdrawStake(&_ContractV07.TransactOpts, withdrawAddress)
+}
+
+// WithdrawStake is a paid mutator transaction binding the contract method 0xc23a5cea.
+//
+// Solidity: function withdrawStake(address withdrawAddress) returns()
+func (_ContractV07 *ContractV07TransactorSession) WithdrawStake(withdrawAddress common.Address) (*types.Transaction, error) {
+	return _ContractV07.Contract.WithdrawStake(&_ContractV07.TransactOpts, withdrawAddress)
+}
+
+// WithdrawTo is a paid mutator transaction binding the contract method 0x205c2878.
+//
+// Solidity: function withdrawTo(address withdrawAddress, uint256 amount) returns()
+func (_ContractV07 *ContractV07Transactor) WithdrawTo(opts *bind.TransactOpts, withdrawAddress common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _ContractV07.contract.Transact(opts, "withdrawTo", withdrawAddress, amount)
+}
+
+// WithdrawTo is a paid mutator transaction binding the contract method 0x205c2878.
+//
+// Solidity: function withdrawTo(address withdrawAddress, uint256 amount) returns()
+func (_ContractV07 *ContractV07Session) WithdrawTo(withdrawAddress common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _ContractV07.Contract.WithdrawTo(&_ContractV07.TransactOpts, withdrawAddress, amount)
+}
+
+// WithdrawTo is a paid mutator transaction binding the contract method 0x205c2878.
+//
+// Solidity: function withdrawTo(address withdrawAddress, uint256 amount) returns()
+func (_ContractV07 *ContractV07TransactorSession) WithdrawTo(withdrawAddress common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _ContractV07.Contract.WithdrawTo(&_ContractV07.TransactOpts, withdrawAddress, amount)
+}
+
+// ContractV07OwnershipTransferredIterator is returned from FilterOwnershipTransferred and is used to iterate over the raw logs and unpacked data for OwnershipTransferred events raised by the ContractV07 contract.
+type ContractV07OwnershipTransferredIterator struct {
+	Event *ContractV07OwnershipTransferred // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractV07OwnershipTransferredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractV07OwnershipTransferred)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractV07OwnershipTransferred)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractV07OwnershipTransferredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractV07OwnershipTransferredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractV07OwnershipTransferred represents a OwnershipTransferred event raised by the ContractV07 contract.
+type ContractV07OwnershipTransferred struct {
+	PreviousOwner common.Address
+	NewOwner      common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnershipTransferred is a free log retrieval operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_ContractV07 *ContractV07Filterer) FilterOwnershipTransferred(opts *bind.FilterOpts, previousOwner []common.Address, newOwner []common.Address) (*ContractV07OwnershipTransferredIterator, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _ContractV07.contract.FilterLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractV07OwnershipTransferredIterator{contract: _ContractV07.contract, event: "OwnershipTransferred", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnershipTransferred is a free log subscription operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_ContractV07 *ContractV07Filterer) WatchOwnershipTransferred(opts *bind.WatchOpts, sink chan<- *ContractV07OwnershipTransferred, previousOwner []common.Address, newOwner []common.Address) (event.Subscription, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _ContractV07.contract.WatchLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractV07OwnershipTransferred)
+				if err := _ContractV07.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnershipTransferred is a log parse operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_ContractV07 *ContractV07Filterer) ParseOwnershipTransferred(log types.Log) (*ContractV07OwnershipTransferred, error) {
+	event := new(ContractV07OwnershipTransferred)
+	if err := _ContractV07.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
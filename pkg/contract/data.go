@@ -2,7 +2,6 @@ package contract
 
 import (
 	"math/big"
-	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -15,11 +14,17 @@ type Data struct {
 	ExchangeRate *big.Int
 }
 
-func NewData(pm common.Address, token common.Address, exchangeRate *big.Int) *Data {
+func NewData(
+	pm common.Address,
+	token common.Address,
+	exchangeRate *big.Int,
+	validAfter *big.Int,
+	validUntil *big.Int,
+) *Data {
 	return &Data{
 		Paymaster:    pm,
-		ValidUntil:   big.NewInt(int64(time.Now().Add(time.Hour).Unix())),
-		ValidAfter:   big.NewInt(0),
+		ValidUntil:   validUntil,
+		ValidAfter:   validAfter,
 		ERC20Token:   token,
 		ExchangeRate: exchangeRate,
 	}
@@ -1,11 +1,63 @@
 package contract
 
 import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
 )
 
+// paymasterGasLimitsOffset and paymasterDataOffset are the positions of the packed paymaster gas limits
+// within an EntryPoint v0.7 paymasterAndData.
+const (
+	paymasterGasLimitsOffset = 20
+	paymasterDataOffset      = 52
+)
+
+func getHashArgs() abi.Arguments {
+	return abi.Arguments{
+		{Name: "sender", Type: address},
+		{Name: "nonce", Type: uint256},
+		{Name: "hashInitCode", Type: bytes32},
+		{Name: "hashCallData", Type: bytes32},
+		{Name: "callGasLimit", Type: uint256},
+		{Name: "verificationGasLimit", Type: uint256},
+		{Name: "preVerificationGas", Type: uint256},
+		{Name: "maxFeePerGas", Type: uint256},
+		{Name: "maxPriorityFeePerGas", Type: uint256},
+		{Name: "chainId", Type: uint256},
+		{Name: "paymaster", Type: address},
+		{Name: "validUntil", Type: uint48},
+		{Name: "validAfter", Type: uint48},
+		{Name: "erc20Token", Type: address},
+		{Name: "exchangeRate", Type: uint256},
+	}
+}
+
+func getHashV07Args() abi.Arguments {
+	return abi.Arguments{
+		{Name: "sender", Type: address},
+		{Name: "nonce", Type: uint256},
+		{Name: "hashInitCode", Type: bytes32},
+		{Name: "hashCallData", Type: bytes32},
+		{Name: "accountGasLimits", Type: bytes32},
+		{Name: "paymasterGasLimits", Type: uint256},
+		{Name: "preVerificationGas", Type: uint256},
+		{Name: "gasFees", Type: bytes32},
+		{Name: "chainId", Type: uint256},
+		{Name: "paymaster", Type: address},
+		{Name: "validUntil", Type: uint48},
+		{Name: "validAfter", Type: uint48},
+		{Name: "erc20Token", Type: address},
+		{Name: "exchangeRate", Type: uint256},
+	}
+}
+
+// GetHash calls getHash on the paymaster contract. ComputeHash should be used instead where possible since
+// it does not require an RPC round trip.
 func GetHash(
 	eth *ethclient.Client,
 	op *userop.UserOperation,
@@ -25,3 +77,89 @@ func GetHash(
 		data.ExchangeRate,
 	)
 }
+
+// GetHashV07 is the EntryPoint v0.7 equivalent of GetHash.
+func GetHashV07(
+	eth *ethclient.Client,
+	op PackedUserOperation,
+	data *Data,
+) ([32]byte, error) {
+	pm, err := NewContractV07(data.Paymaster, eth)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return pm.GetHash(
+		&bind.CallOpts{},
+		op,
+		data.ValidUntil,
+		data.ValidAfter,
+		data.ERC20Token,
+		data.ExchangeRate,
+	)
+}
+
+// ComputeHash returns the same value as the paymaster's getHash function without making an eth_call.
+func ComputeHash(
+	op *userop.UserOperation,
+	chainID *big.Int,
+	data *Data,
+) ([32]byte, error) {
+	packed, err := getHashArgs().Pack(
+		op.Sender,
+		op.Nonce,
+		crypto.Keccak256Hash(op.InitCode),
+		crypto.Keccak256Hash(op.CallData),
+		op.CallGasLimit,
+		op.VerificationGasLimit,
+		op.PreVerificationGas,
+		op.MaxFeePerGas,
+		op.MaxPriorityFeePerGas,
+		chainID,
+		data.Paymaster,
+		data.ValidUntil,
+		data.ValidAfter,
+		data.ERC20Token,
+		data.ExchangeRate,
+	)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return crypto.Keccak256Hash(packed), nil
+}
+
+// ComputeHashV07 is the EntryPoint v0.7 equivalent of ComputeHash. The paymaster gas limits in the op's
+// paymasterAndData are part of the hash but the paymaster data itself is not.
+func ComputeHashV07(
+	op PackedUserOperation,
+	chainID *big.Int,
+	data *Data,
+) ([32]byte, error) {
+	pmGasLimits := big.NewInt(0)
+	if len(op.PaymasterAndData) >= paymasterDataOffset {
+		pmGasLimits.SetBytes(op.PaymasterAndData[paymasterGasLimitsOffset:paymasterDataOffset])
+	}
+
+	packed, err := getHashV07Args().Pack(
+		op.Sender,
+		op.Nonce,
+		crypto.Keccak256Hash(op.InitCode),
+		crypto.Keccak256Hash(op.CallData),
+		op.AccountGasLimits,
+		pmGasLimits,
+		op.PreVerificationGas,
+		op.GasFees,
+		chainID,
+		data.Paymaster,
+		data.ValidUntil,
+		data.ValidAfter,
+		data.ERC20Token,
+		data.ExchangeRate,
+	)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return crypto.Keccak256Hash(packed), nil
+}
@@ -0,0 +1,184 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+)
+
+// The expected hashes below are keccak256(abi.encode(...)) of the values in each case, encoded in the same
+// order as getHash on the paymaster contract. They were generated independently of this package and must not
+// be updated to match a change in ComputeHash or ComputeHashV07.
+var (
+	testSender    = common.HexToAddress("0x1306b01bC3e4AD202612D3843387e94737673F53")
+	testPaymaster = common.HexToAddress("0x9D6AC51b972544251Fcc0F2902e633E3f9BD3f29")
+	testToken     = common.HexToAddress("0x3870419Ba2BBf0127060bCB37f69A1b1C090992B")
+	testInitCode  = hexutil.MustDecode(
+		"0x9406cc6185a346906296840746125a0e449764545fbfb9cf000000000000000000000000d2e59bbad8f9c9b1c8e1b6" +
+			"ab7a57e2c32c6ee2a40000000000000000000000000000000000000000000000000000000000000000",
+	)
+	testCallData = hexutil.MustDecode(
+		"0xb61d27f60000000000000000000000001306b01bc3e4ad202612d3843387e94737673f530000000000000000000000" +
+			"0000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000" +
+			"0000000000600000000000000000000000000000000000000000000000000000000000000000",
+	)
+)
+
+func packUint128s(high int64, low int64) [32]byte {
+	var out [32]byte
+	copy(out[:16], common.LeftPadBytes(big.NewInt(high).Bytes(), 16))
+	copy(out[16:], common.LeftPadBytes(big.NewInt(low).Bytes(), 16))
+	return out
+}
+
+func TestComputeHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      *userop.UserOperation
+		chainID *big.Int
+		data    *Data
+		want    string
+	}{
+		{
+			name: "payg",
+			op: &userop.UserOperation{
+				Sender:               testSender,
+				Nonce:                big.NewInt(1),
+				InitCode:             []byte{},
+				CallData:             testCallData,
+				CallGasLimit:         big.NewInt(35000),
+				VerificationGasLimit: big.NewInt(70000),
+				PreVerificationGas:   big.NewInt(48000),
+				MaxFeePerGas:         big.NewInt(2000000000),
+				MaxPriorityFeePerGas: big.NewInt(1000000000),
+				PaymasterAndData:     []byte{},
+				Signature:            []byte{},
+			},
+			chainID: big.NewInt(1),
+			data: NewData(
+				testPaymaster,
+				common.Address{},
+				big.NewInt(0),
+				big.NewInt(1700000000),
+				big.NewInt(1700003600),
+			),
+			want: "0x4d86f29fbe601d473baa2ecaf6f113606cfeaad8e59a32676b43da4a939f35c8",
+		},
+		{
+			name: "erc20 with initCode",
+			op: &userop.UserOperation{
+				Sender:               testSender,
+				Nonce:                big.NewInt(0),
+				InitCode:             testInitCode,
+				CallData:             testCallData,
+				CallGasLimit:         big.NewInt(35000),
+				VerificationGasLimit: big.NewInt(350000),
+				PreVerificationGas:   big.NewInt(48000),
+				MaxFeePerGas:         big.NewInt(2000000000),
+				MaxPriorityFeePerGas: big.NewInt(1000000000),
+				PaymasterAndData:     testPaymaster.Bytes(),
+				Signature:            hexutil.MustDecode("0xdeadbeef"),
+			},
+			chainID: big.NewInt(137),
+			data: NewData(
+				testPaymaster,
+				testToken,
+				big.NewInt(2500000000000000),
+				big.NewInt(0),
+				big.NewInt(1700003600),
+			),
+			want: "0xf4824d0f941294ceca1699c4a2f6f752680aa632693e834a8793d35bb6311c2c",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ComputeHash(tc.op, tc.chainID, tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hexutil.Encode(got[:]) != tc.want {
+				t.Errorf("got %s, want %s", hexutil.Encode(got[:]), tc.want)
+			}
+		})
+	}
+}
+
+func TestComputeHashV07(t *testing.T) {
+	paygGasLimits := packUint128s(50000, 0)
+	pmGasLimits := packUint128s(50000, 40000)
+
+	tests := []struct {
+		name    string
+		op      PackedUserOperation
+		chainID *big.Int
+		data    *Data
+		want    string
+	}{
+		{
+			name: "payg",
+			op: PackedUserOperation{
+				Sender:             testSender,
+				Nonce:              big.NewInt(1),
+				InitCode:           []byte{},
+				CallData:           testCallData,
+				AccountGasLimits:   packUint128s(70000, 35000),
+				PreVerificationGas: big.NewInt(48000),
+				GasFees:            packUint128s(1000000000, 2000000000),
+				PaymasterAndData:   append(testPaymaster.Bytes(), paygGasLimits[:]...),
+				Signature:          []byte{},
+			},
+			chainID: big.NewInt(1),
+			data: NewData(
+				testPaymaster,
+				common.Address{},
+				big.NewInt(0),
+				big.NewInt(1700000000),
+				big.NewInt(1700003600),
+			),
+			want: "0x33c294182e93059a7bbd8079ee2ff2cf8bd6937a5153eee59082c13bf101f04a",
+		},
+		{
+			// The paymaster data after the gas limits is not part of the hash.
+			name: "erc20 with initCode and paymaster data",
+			op: PackedUserOperation{
+				Sender:             testSender,
+				Nonce:              big.NewInt(0),
+				InitCode:           testInitCode,
+				CallData:           testCallData,
+				AccountGasLimits:   packUint128s(350000, 35000),
+				PreVerificationGas: big.NewInt(48000),
+				GasFees:            packUint128s(1000000000, 2000000000),
+				PaymasterAndData: append(
+					append(testPaymaster.Bytes(), pmGasLimits[:]...),
+					hexutil.MustDecode("0xdeadbeef")...,
+				),
+				Signature: hexutil.MustDecode("0xdeadbeef"),
+			},
+			chainID: big.NewInt(137),
+			data: NewData(
+				testPaymaster,
+				testToken,
+				big.NewInt(2500000000000000),
+				big.NewInt(0),
+				big.NewInt(1700003600),
+			),
+			want: "0xa6e4cdf50381983c0091db9f6f6411912ec6ee6aa162295e40155c7c90c3e962",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ComputeHashV07(tc.op, tc.chainID, tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hexutil.Encode(got[:]) != tc.want {
+				t.Errorf("got %s, want %s", hexutil.Encode(got[:]), tc.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package contract
 
 import (
+	"errors"
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func getAbiArgs() abi.Arguments {
@@ -13,7 +17,9 @@ func getAbiArgs() abi.Arguments {
 	}
 }
 
-func EncodePaymasterAndData(
+// EncodePaymasterData returns the paymaster specific data and signature without the paymaster address
+// prefix. For EntryPoint v0.7 this is the paymasterData field of a UserOperation.
+func EncodePaymasterData(
 	data *Data,
 	signature []byte,
 ) ([]byte, error) {
@@ -23,8 +29,45 @@ func EncodePaymasterAndData(
 		return nil, err
 	}
 
+	return append(packed, signature...), nil
+}
+
+func EncodePaymasterAndData(
+	data *Data,
+	signature []byte,
+) ([]byte, error) {
+	encoded, err := EncodePaymasterData(data, signature)
+	if err != nil {
+		return nil, err
+	}
+
 	concat := data.Paymaster.Bytes()
-	concat = append(concat, packed...)
-	concat = append(concat, signature...)
+	concat = append(concat, encoded...)
 	return concat, nil
 }
+
+// DecodePaymasterAndData returns the Data and signature from an encoded paymasterAndData.
+func DecodePaymasterAndData(pnd []byte) (*Data, []byte, error) {
+	if len(pnd) < common.AddressLength {
+		return nil, nil, errors.New("paymasterAndData: too short")
+	}
+
+	args := getAbiArgs()
+	encodedLen := common.AddressLength + len(args)*32
+	if len(pnd) < encodedLen {
+		return nil, nil, errors.New("paymasterAndData: too short")
+	}
+
+	values, err := args.Unpack(pnd[common.AddressLength:encodedLen])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &Data{
+		Paymaster:    common.BytesToAddress(pnd[:common.AddressLength]),
+		ValidUntil:   values[0].(*big.Int),
+		ValidAfter:   values[1].(*big.Int),
+		ERC20Token:   values[2].(common.Address),
+		ExchangeRate: values[3].(*big.Int),
+	}, pnd[encodedLen:], nil
+}
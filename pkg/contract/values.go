@@ -6,4 +6,5 @@ var (
 	uint256, _ = abi.NewType("uint256", "", nil)
 	uint48, _  = abi.NewType("uint48", "", nil)
 	address, _ = abi.NewType("address", "", nil)
+	bytes32, _ = abi.NewType("bytes32", "", nil)
 )
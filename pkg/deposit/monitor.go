@@ -0,0 +1,240 @@
+// Package deposit monitors the EntryPoint deposit and stake of all configured paymasters.
+package deposit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-logr/logr"
+	"github.com/stackup-wallet/stackup-bundler/pkg/entrypoint"
+	"github.com/stackup-wallet/stackup-paymaster/internal/o11y"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ErrInsufficientDeposit is returned when the deposit of a paymaster can't cover the max cost of an op.
+var ErrInsufficientDeposit = errors.New("paymaster deposit too low")
+
+// Opts are the settings and thresholds for the Monitor. A nil threshold is not checked.
+type Opts struct {
+	// ChainID is added as an attribute to all gauges.
+	ChainID *big.Int
+
+	// MinDeposit is the deposit in wei below which a warning is logged.
+	MinDeposit *big.Int
+
+	// MinStake is the stake in wei below which a warning is logged.
+	MinStake *big.Int
+
+	// Enforce will cause Check to reject ops that the last known deposit can't cover.
+	Enforce bool
+}
+
+// InsufficientDepositData is the data field of the JSON-RPC error returned with ErrInsufficientDeposit.
+type InsufficientDepositData struct {
+	EntryPoint common.Address `json:"entryPoint"`
+	Paymaster  common.Address `json:"paymaster"`
+	Deposit    string         `json:"deposit"`
+	Required   string         `json:"required"`
+}
+
+// Info is the last known deposit and stake of a paymaster on an EntryPoint.
+type Info struct {
+	EntryPoint      common.Address
+	Paymaster       common.Address
+	Deposit         *big.Int
+	Staked          bool
+	Stake           *big.Int
+	UnstakeDelaySec uint32
+	UpdatedAt       time.Time
+}
+
+type infoKey struct {
+	ep common.Address
+	pm common.Address
+}
+
+// Monitor polls the EntryPoint for the deposit and stake of every configured paymaster.
+type Monitor struct {
+	eth    *ethclient.Client
+	ep2pms map[common.Address][]common.Address
+	opts   *Opts
+	logger logr.Logger
+
+	mu   sync.RWMutex
+	info map[infoKey]*Info
+}
+
+// New returns a Monitor for all paymasters in ep2pms.
+func New(
+	eth *ethclient.Client,
+	ep2pms map[common.Address][]common.Address,
+	opts *Opts,
+	l logr.Logger,
+) *Monitor {
+	return &Monitor{
+		eth:    eth,
+		ep2pms: ep2pms,
+		opts:   opts,
+		logger: l,
+		info:   make(map[infoKey]*Info),
+	}
+}
+
+// Run fetches the initial deposits and then polls at the given interval in a separate goroutine. It also
+// registers the deposit and stake of each paymaster as OTEL gauges.
+func (m *Monitor) Run(interval time.Duration) error {
+	if err := m.registerMetrics(); err != nil {
+		return err
+	}
+	m.poll()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			m.poll()
+		}
+	}()
+	return nil
+}
+
+// Get returns the last known Info for a paymaster on an EntryPoint or nil if it has not been fetched yet.
+func (m *Monitor) Get(ep common.Address, pm common.Address) *Info {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.info[infoKey{ep, pm}]
+}
+
+// Check returns an error wrapping ErrInsufficientDeposit if enforcement is enabled and the last known
+// deposit of the paymaster is less than the maxCost. A nil Monitor will allow all ops.
+func (m *Monitor) Check(ep common.Address, pm common.Address, maxCost *big.Int) error {
+	if m == nil || !m.opts.Enforce {
+		return nil
+	}
+
+	info := m.Get(ep, pm)
+	if info == nil {
+		return nil
+	}
+	if info.Deposit.Cmp(maxCost) < 0 {
+		return rpcerrors.New(
+			rpcerrors.CodeInsufficientDeposit,
+			fmt.Errorf(
+				"%w: %s has %s wei, requires %s wei",
+				ErrInsufficientDeposit,
+				pm.Hex(),
+				info.Deposit,
+				maxCost,
+			),
+			&InsufficientDepositData{
+				EntryPoint: ep,
+				Paymaster:  pm,
+				Deposit:    info.Deposit.String(),
+				Required:   maxCost.String(),
+			},
+		)
+	}
+	return nil
+}
+
+func (m *Monitor) poll() {
+	for ep, pms := range m.ep2pms {
+		contract, err := entrypoint.NewEntrypoint(ep, m.eth)
+		if err != nil {
+			m.logger.Error(err, "deposit monitor error", "entrypoint", ep.Hex())
+			continue
+		}
+
+		for _, pm := range pms {
+			l := m.logger.WithValues("entrypoint", ep.Hex(), "paymaster", pm.Hex())
+			dep, err := contract.GetDepositInfo(&bind.CallOpts{Context: context.Background()}, pm)
+			if err != nil {
+				l.Error(err, "deposit monitor error")
+				continue
+			}
+
+			info := &Info{
+				EntryPoint:      ep,
+				Paymaster:       pm,
+				Deposit:         dep.Deposit,
+				Staked:          dep.Staked,
+				Stake:           dep.Stake,
+				UnstakeDelaySec: dep.UnstakeDelaySec,
+				UpdatedAt:       time.Now(),
+			}
+			m.mu.Lock()
+			m.info[infoKey{ep, pm}] = info
+			m.mu.Unlock()
+
+			if m.opts.MinDeposit != nil && info.Deposit.Cmp(m.opts.MinDeposit) < 0 {
+				l.Info(
+					"warning: paymaster deposit below threshold",
+					"deposit", info.Deposit.String(),
+					"min_deposit", m.opts.MinDeposit.String(),
+				)
+			}
+			if m.opts.MinStake != nil && (!info.Staked || info.Stake.Cmp(m.opts.MinStake) < 0) {
+				l.Info(
+					"warning: paymaster stake below threshold",
+					"stake", info.Stake.String(),
+					"staked", info.Staked,
+					"min_stake", m.opts.MinStake.String(),
+				)
+			}
+		}
+	}
+}
+
+func (m *Monitor) registerMetrics() error {
+	meter := o11y.Meter("github.com/stackup-wallet/stackup-paymaster/pkg/deposit")
+	deposit, err := meter.Float64ObservableGauge(
+		"paymaster.deposit",
+		metric.WithDescription("The EntryPoint deposit of the paymaster"),
+		metric.WithUnit("wei"),
+	)
+	if err != nil {
+		return err
+	}
+	stake, err := meter.Float64ObservableGauge(
+		"paymaster.stake",
+		metric.WithDescription("The EntryPoint stake of the paymaster"),
+		metric.WithUnit("wei"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(
+		func(_ context.Context, o metric.Observer) error {
+			m.mu.RLock()
+			defer m.mu.RUnlock()
+
+			for _, info := range m.info {
+				attrs := metric.WithAttributes(
+					attribute.Int64("chain_id", m.opts.ChainID.Int64()),
+					attribute.String("entrypoint", info.EntryPoint.Hex()),
+					attribute.String("paymaster", info.Paymaster.Hex()),
+				)
+				d, _ := new(big.Float).SetInt(info.Deposit).Float64()
+				s, _ := new(big.Float).SetInt(info.Stake).Float64()
+				o.ObserveFloat64(deposit, d, attrs)
+				o.ObserveFloat64(stake, s, attrs)
+			}
+			return nil
+		},
+		deposit,
+		stake,
+	)
+	return err
+}
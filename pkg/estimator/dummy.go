@@ -0,0 +1,18 @@
+package estimator
+
+import "github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+
+// DummyPaymasterAndData returns a placeholder paymasterAndData for the given data. It is required to
+// calculate an acceptable preVerificationGas prior to paymaster approval. The placeholder is encoded the same
+// way as the final paymasterAndData with only the signature replaced, so it has the same length and the same
+// ratio of zero to non-zero bytes. This means the preVerificationGas calculated with it will not change once
+// the real signature is added.
+func DummyPaymasterAndData(data *contract.Data) ([]byte, error) {
+	return contract.EncodePaymasterAndData(data, dummySignature)
+}
+
+// DummyPaymasterData is the v0.7 equivalent of DummyPaymasterAndData. It only includes the paymaster specific
+// data since the paymaster address and gas limits are separate fields on a v0.7 UserOperation.
+func DummyPaymasterData(data *contract.Data) ([]byte, error) {
+	return contract.EncodePaymasterData(data, dummySignature)
+}
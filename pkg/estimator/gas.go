@@ -8,9 +8,10 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stackup-wallet/stackup-bundler/pkg/gas"
-	"github.com/stackup-wallet/stackup-bundler/pkg/signer"
 	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
 	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/keyring"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
 )
 
 func updateOpVerificationGasLimit(op *userop.UserOperation, val *big.Int) (*userop.UserOperation, error) {
@@ -34,13 +35,14 @@ func updateOpCallGasLimit(op *userop.UserOperation, val *big.Int) (*userop.UserO
 func updateOpPreVerificationGas(
 	op *userop.UserOperation,
 	ov *gas.Overhead,
+	limits *Limits,
 ) (*userop.UserOperation, error) {
 	opData, err := op.ToMap()
 	if err != nil {
 		return nil, err
 	}
 
-	pvg, err := ov.CalcPreVerificationGasWithBuffer(op)
+	pvg, err := calcPreVerificationGas(op, ov, limits)
 	if err != nil {
 		return nil, err
 	}
@@ -61,45 +63,73 @@ func updateOpPaymasterAndData(
 	return userop.New(opData)
 }
 
+// calcPreVerificationGas returns the preVerificationGas of the op with the buffer and bounds from limits. If
+// a buffer is not set, the default buffer of the overhead for the network is used instead.
+func calcPreVerificationGas(op *userop.UserOperation, ov *gas.Overhead, limits *Limits) (*big.Int, error) {
+	var pvg *big.Int
+	var err error
+	if limits.PreVerificationGasBuffer != nil {
+		pvg, err = ov.CalcPreVerificationGas(op)
+	} else {
+		pvg, err = ov.CalcPreVerificationGasWithBuffer(op)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return apply(
+		"preVerificationGas",
+		pvg,
+		limits.PreVerificationGasBuffer,
+		limits.MinPreVerificationGas,
+		limits.MaxPreVerificationGas,
+	)
+}
+
 type GasEstimator struct {
-	signer  *signer.EOA
+	keys    *keyring.Keyring
 	rpc     *rpc.Client
 	eth     *ethclient.Client
 	chainID *big.Int
-	ov      *gas.Overhead
+	ov      Overheads
+	ovV07   *OverheadV07
+	limits  *Limits
 }
 
+// New returns a GasEstimator with the default Limits for the network. The limits can be overridden for each
+// op.
 func New(
-	signer *signer.EOA,
+	keys *keyring.Keyring,
 	rpc *rpc.Client,
 	eth *ethclient.Client,
 	chain *big.Int,
-	ov *gas.Overhead,
+	ov Overheads,
+	ovV07 *OverheadV07,
+	limits *Limits,
 ) *GasEstimator {
 	return &GasEstimator{
-		signer:  signer,
+		keys:    keys,
 		rpc:     rpc,
 		eth:     eth,
 		chainID: chain,
 		ov:      ov,
+		ovV07:   ovV07,
+		limits:  limits,
 	}
 }
 
+// OverrideOpGasLimitsForPND returns a copy of the op with gas limits estimated for the given paymaster data.
+// Any field set on override replaces the default Limits of the estimator.
 func (g *GasEstimator) OverrideOpGasLimitsForPND(
 	op *userop.UserOperation,
 	ep common.Address,
 	data *contract.Data,
+	override *Limits,
 ) (*userop.UserOperation, error) {
+	limits := g.limits.Merge(override)
+
 	// Generate a PND for EstimateGas.
-	hash, err := contract.GetHash(g.eth, op, data)
-	if err != nil {
-		return nil, err
-	}
-	sig, err := contract.Sign(hash[:], g.signer)
-	if err != nil {
-		return nil, err
-	}
-	pnd, err := contract.EncodePaymasterAndData(data, sig)
+	pnd, err := g.keys.SignPaymasterAndData(op, g.chainID, data)
 	if err != nil {
 		return nil, err
 	}
@@ -113,32 +143,70 @@ func (g *GasEstimator) OverrideOpGasLimitsForPND(
 		Rpc:         g.rpc,
 		EntryPoint:  ep,
 		Op:          pmOp,
-		Ov:          g.ov,
+		Ov:          g.ov.get(ep),
 		ChainID:     g.chainID,
-		MaxGasLimit: maxGasLimit,
+		MaxGasLimit: limits.maxGasLimit(),
 		Tracer:      "bundlerExecutorTracer",
 	})
 	if err != nil {
-		return nil, err
+		return nil, rpcerrors.Simulation(err)
 	}
 
 	// Update gas fields.
-	pmOp, err = updateOpPaymasterAndData(pmOp, DummyPaymasterAndDataHex)
+	dummy, err := DummyPaymasterAndData(data)
+	if err != nil {
+		return nil, err
+	}
+	pmOp, err = updateOpPaymasterAndData(pmOp, hexutil.Encode(dummy))
+	if err != nil {
+		return nil, err
+	}
+	vglWithLimits, err := apply(
+		"verificationGasLimit",
+		big.NewInt(int64(vgl)),
+		limits.VerificationGasBuffer,
+		limits.MinVerificationGasLimit,
+		limits.MaxVerificationGasLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
-	pmOp, err = updateOpVerificationGasLimit(pmOp, big.NewInt(int64(vgl)))
+	pmOp, err = updateOpVerificationGasLimit(pmOp, vglWithLimits)
 	if err != nil {
 		return nil, err
 	}
-	pmOp, err = updateOpCallGasLimit(pmOp, big.NewInt(int64(cgl)))
+	cglWithLimits, err := apply(
+		"callGasLimit",
+		big.NewInt(int64(cgl)),
+		limits.CallGasBuffer,
+		limits.MinCallGasLimit,
+		limits.MaxCallGasLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
-	pmOp, err = updateOpPreVerificationGas(pmOp, g.ov)
+	pmOp, err = updateOpCallGasLimit(pmOp, cglWithLimits)
 	if err != nil {
 		return nil, err
 	}
+	pmOp, err = updateOpPreVerificationGas(pmOp, g.ov.get(ep), limits)
+	if err != nil {
+		return nil, err
+	}
+	if err := limits.checkTotal(
+		pmOp.PreVerificationGas,
+		pmOp.VerificationGasLimit,
+		pmOp.CallGasLimit,
+	); err != nil {
+		return nil, err
+	}
 
 	return pmOp, nil
 }
+
+// CheckOpGasLimits returns an error if the gas limits already set on the op exceed the bounds that
+// OverrideOpGasLimitsForPND would enforce. Any field set on override replaces the default Limits of the
+// estimator.
+func (g *GasEstimator) CheckOpGasLimits(op *userop.UserOperation, override *Limits) error {
+	return g.limits.Merge(override).check(op.PreVerificationGas, op.VerificationGasLimit, op.CallGasLimit)
+}
@@ -0,0 +1,160 @@
+package estimator
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+)
+
+// OverrideOpGasLimitsForPNDV07 is the EntryPoint v0.7 equivalent of OverrideOpGasLimitsForPND. The
+// verificationGasLimit and callGasLimit are estimated by simulating the calls that the EntryPoint makes to
+// the account. The paymaster gas limits are not estimated and are set to the given values instead.
+func (g *GasEstimator) OverrideOpGasLimitsForPNDV07(
+	op *packedop.UserOperation,
+	ep common.Address,
+	data *contract.Data,
+	pmVerificationGasLimit *big.Int,
+	pmPostOpGasLimit *big.Int,
+	override *Limits,
+) (*packedop.UserOperation, error) {
+	limits := g.limits.Merge(override)
+	dummy, err := DummyPaymasterData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pmOp := *op
+	pmOp.Paymaster = data.Paymaster
+	pmOp.PaymasterVerificationGasLimit = pmVerificationGasLimit
+	pmOp.PaymasterPostOpGasLimit = pmPostOpGasLimit
+	pmOp.PaymasterData = dummy
+
+	// Estimate the account gas limits. Deployment and validation share the verificationGasLimit.
+	maxGas := limits.maxGasLimit()
+	calls, err := newSimCalls(&pmOp, ep, g.chainID, func(int) *big.Int { return maxGas })
+	if err != nil {
+		return nil, err
+	}
+	res, err := simulateV07(g.rpc, ep, calls, maxGas)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSimResults(&pmOp, calls, res); err != nil {
+		return nil, err
+	}
+	vgl := big.NewInt(0)
+	cgl := big.NewInt(0)
+	deployed := big.NewInt(0)
+	for i, c := range calls {
+		switch c.kind {
+		case simDeploy:
+			deployed = res[i].gasUsed
+			vgl.Add(vgl, res[i].gasUsed)
+		case simValidate:
+			vgl.Add(vgl, res[i].gasUsed)
+		case simExecute:
+			// Allow for the 1/64th of gas that is held back from each nested call.
+			cgl.Mul(res[i].gasUsed, big.NewInt(64))
+			cgl.Div(cgl, big.NewInt(63))
+		}
+	}
+	pmOp.VerificationGasLimit, err = apply(
+		"verificationGasLimit",
+		vgl,
+		limits.VerificationGasBuffer,
+		limits.MinVerificationGasLimit,
+		limits.MaxVerificationGasLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	pmOp.CallGasLimit, err = apply(
+		"callGasLimit",
+		cgl,
+		limits.CallGasBuffer,
+		limits.MinCallGasLimit,
+		limits.MaxCallGasLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the op still succeeds with the estimated gas limits.
+	calls, err = newSimCalls(&pmOp, ep, g.chainID, func(kind int) *big.Int {
+		switch kind {
+		case simValidate:
+			if pmOp.VerificationGasLimit.Cmp(deployed) < 0 {
+				return big.NewInt(0)
+			}
+			return big.NewInt(0).Sub(pmOp.VerificationGasLimit, deployed)
+		case simExecute:
+			return pmOp.CallGasLimit
+		default:
+			return pmOp.VerificationGasLimit
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := simulateV07(g.rpc, ep, calls, maxGas); err != nil {
+		return nil, err
+	}
+
+	pvg, err := calcPreVerificationGasV07(&pmOp, ep, g.ovV07, limits)
+	if err != nil {
+		return nil, err
+	}
+	pmOp.PreVerificationGas = pvg
+
+	if err := limits.checkTotal(
+		pmOp.PreVerificationGas,
+		pmOp.VerificationGasLimit,
+		pmOp.CallGasLimit,
+		pmOp.PaymasterVerificationGasLimit,
+		pmOp.PaymasterPostOpGasLimit,
+	); err != nil {
+		return nil, err
+	}
+
+	return &pmOp, nil
+}
+
+// calcPreVerificationGasV07 is the EntryPoint v0.7 equivalent of calcPreVerificationGas.
+func calcPreVerificationGasV07(
+	op *packedop.UserOperation,
+	ep common.Address,
+	ov *OverheadV07,
+	limits *Limits,
+) (*big.Int, error) {
+	var pvg *big.Int
+	var err error
+	if limits.PreVerificationGasBuffer != nil {
+		pvg, err = ov.CalcPreVerificationGas(op, ep)
+	} else {
+		pvg, err = ov.CalcPreVerificationGasWithBuffer(op, ep)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return apply(
+		"preVerificationGas",
+		pvg,
+		limits.PreVerificationGasBuffer,
+		limits.MinPreVerificationGas,
+		limits.MaxPreVerificationGas,
+	)
+}
+
+// CheckOpGasLimitsV07 is the EntryPoint v0.7 equivalent of CheckOpGasLimits.
+func (g *GasEstimator) CheckOpGasLimitsV07(op *packedop.UserOperation, override *Limits) error {
+	return g.limits.Merge(override).check(
+		op.PreVerificationGas,
+		op.VerificationGasLimit,
+		op.CallGasLimit,
+		op.PaymasterVerificationGasLimit,
+		op.PaymasterPostOpGasLimit,
+	)
+}
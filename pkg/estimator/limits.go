@@ -0,0 +1,165 @@
+package estimator
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+// Limits are the buffers and bounds applied to estimated gas values. Buffers are a percentage added on top
+// of an estimate before it is raised to its min. An estimate that is still above its max is rejected instead
+// of lowered, since a limit below the estimate would fail on chain. Any field that is nil is not applied.
+type Limits struct {
+	VerificationGasBuffer    *int64 `json:"verificationGasBuffer"    yaml:"verificationGasBuffer"`
+	CallGasBuffer            *int64 `json:"callGasBuffer"            yaml:"callGasBuffer"`
+	PreVerificationGasBuffer *int64 `json:"preVerificationGasBuffer" yaml:"preVerificationGasBuffer"`
+
+	MinVerificationGasLimit *big.Int `json:"minVerificationGasLimit" yaml:"minVerificationGasLimit"`
+	MaxVerificationGasLimit *big.Int `json:"maxVerificationGasLimit" yaml:"maxVerificationGasLimit"`
+	MinCallGasLimit         *big.Int `json:"minCallGasLimit"         yaml:"minCallGasLimit"`
+	MaxCallGasLimit         *big.Int `json:"maxCallGasLimit"         yaml:"maxCallGasLimit"`
+	MinPreVerificationGas   *big.Int `json:"minPreVerificationGas"   yaml:"minPreVerificationGas"`
+	MaxPreVerificationGas   *big.Int `json:"maxPreVerificationGas"   yaml:"maxPreVerificationGas"`
+
+	// MaxTotalGas is the max sum of all gas limits on the op, including the paymaster gas limits on v0.7.
+	MaxTotalGas *big.Int `json:"maxTotalGas" yaml:"maxTotalGas"`
+}
+
+func pick[T any](base *T, override *T) *T {
+	if override != nil {
+		return override
+	}
+	return base
+}
+
+// Merge returns a copy of the Limits with any field set on override replacing the original. Either Limits
+// can be nil.
+func (l *Limits) Merge(override *Limits) *Limits {
+	if l == nil {
+		l = &Limits{}
+	}
+	if override == nil {
+		override = &Limits{}
+	}
+
+	return &Limits{
+		VerificationGasBuffer:    pick(l.VerificationGasBuffer, override.VerificationGasBuffer),
+		CallGasBuffer:            pick(l.CallGasBuffer, override.CallGasBuffer),
+		PreVerificationGasBuffer: pick(l.PreVerificationGasBuffer, override.PreVerificationGasBuffer),
+		MinVerificationGasLimit:  pick(l.MinVerificationGasLimit, override.MinVerificationGasLimit),
+		MaxVerificationGasLimit:  pick(l.MaxVerificationGasLimit, override.MaxVerificationGasLimit),
+		MinCallGasLimit:          pick(l.MinCallGasLimit, override.MinCallGasLimit),
+		MaxCallGasLimit:          pick(l.MaxCallGasLimit, override.MaxCallGasLimit),
+		MinPreVerificationGas:    pick(l.MinPreVerificationGas, override.MinPreVerificationGas),
+		MaxPreVerificationGas:    pick(l.MaxPreVerificationGas, override.MaxPreVerificationGas),
+		MaxTotalGas:              pick(l.MaxTotalGas, override.MaxTotalGas),
+	}
+}
+
+// Validate returns an error if any buffer would decrease a value or any min is greater than its max. A nil
+// Limits is valid.
+func (l *Limits) Validate() error {
+	if l == nil {
+		return nil
+	}
+
+	for name, b := range map[string]*int64{
+		"verificationGasBuffer":    l.VerificationGasBuffer,
+		"callGasBuffer":            l.CallGasBuffer,
+		"preVerificationGasBuffer": l.PreVerificationGasBuffer,
+	} {
+		if b != nil && *b < 0 {
+			return fmt.Errorf("gas: %s must not be negative", name)
+		}
+	}
+	for name, bounds := range map[string][2]*big.Int{
+		"verificationGasLimit": {l.MinVerificationGasLimit, l.MaxVerificationGasLimit},
+		"callGasLimit":         {l.MinCallGasLimit, l.MaxCallGasLimit},
+		"preVerificationGas":   {l.MinPreVerificationGas, l.MaxPreVerificationGas},
+	} {
+		if bounds[0] != nil && bounds[1] != nil && bounds[0].Cmp(bounds[1]) > 0 {
+			return fmt.Errorf("gas: min %s is greater than max", name)
+		}
+	}
+	return nil
+}
+
+// withBuffer returns val increased by the buffer percentage.
+func withBuffer(val *big.Int, bufferPercent *int64) *big.Int {
+	out := big.NewInt(0).Set(val)
+	if bufferPercent != nil {
+		out.Mul(out, big.NewInt(100+*bufferPercent))
+		out.Div(out, big.NewInt(100))
+	}
+	return out
+}
+
+// apply returns val increased by the buffer and raised to the min. It returns an error if the result is
+// greater than the max.
+func apply(
+	name string,
+	val *big.Int,
+	bufferPercent *int64,
+	lower *big.Int,
+	upper *big.Int,
+) (*big.Int, error) {
+	out := withBuffer(val, bufferPercent)
+	if lower != nil && out.Cmp(lower) < 0 {
+		out.Set(lower)
+	}
+	if err := checkMax(name, out, upper); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// checkMax returns an error if val is greater than the max.
+func checkMax(name string, val *big.Int, upper *big.Int) error {
+	if upper != nil && val != nil && val.Cmp(upper) > 0 {
+		return rpcerrors.InvalidParams(fmt.Errorf("gas: %s of %s exceeds max of %s", name, val, upper))
+	}
+	return nil
+}
+
+// check returns an error if any of the account gas limits is greater than its max, or if the sum of all gas
+// limits is greater than MaxTotalGas. The paymaster gas limits are only included in the total.
+func (l *Limits) check(
+	pvg *big.Int,
+	vgl *big.Int,
+	cgl *big.Int,
+	pmGasLimits ...*big.Int,
+) error {
+	if err := checkMax("preVerificationGas", pvg, l.MaxPreVerificationGas); err != nil {
+		return err
+	}
+	if err := checkMax("verificationGasLimit", vgl, l.MaxVerificationGasLimit); err != nil {
+		return err
+	}
+	if err := checkMax("callGasLimit", cgl, l.MaxCallGasLimit); err != nil {
+		return err
+	}
+	return l.checkTotal(append([]*big.Int{pvg, vgl, cgl}, pmGasLimits...)...)
+}
+
+// maxGasLimit returns the cap on total gas to use during estimation.
+func (l *Limits) maxGasLimit() *big.Int {
+	if l.MaxTotalGas != nil {
+		return l.MaxTotalGas
+	}
+	return defaultMaxGasLimit
+}
+
+// checkTotal returns an error if the sum of all gas limits is greater than MaxTotalGas.
+func (l *Limits) checkTotal(limits ...*big.Int) error {
+	total := big.NewInt(0)
+	for _, gl := range limits {
+		if gl != nil {
+			total.Add(total, gl)
+		}
+	}
+	if max := l.maxGasLimit(); total.Cmp(max) > 0 {
+		return rpcerrors.InvalidParams(fmt.Errorf("gas: total of %s exceeds max of %s", total, max))
+	}
+	return nil
+}
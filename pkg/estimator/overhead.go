@@ -0,0 +1,39 @@
+package estimator
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stackup-wallet/stackup-bundler/pkg/gas"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+// Overheads is the gas.Overhead for each EntryPoint v0.6 of a chain. On networks with an L1 component, the
+// preVerificationGas depends on the EntryPoint that the handleOps transaction is sent to.
+type Overheads map[common.Address]*gas.Overhead
+
+// get returns the gas.Overhead for the EntryPoint or the default if it is not set.
+func (o Overheads) get(ep common.Address) *gas.Overhead {
+	if ov, ok := o[ep]; ok {
+		return ov
+	}
+	return gas.NewDefaultOverhead()
+}
+
+// CalcOptimismPVGWithEthClient is gas.CalcOptimismPVGWithEthClient but returns an error instead of dividing
+// by zero if the L2 gas price of the op is 0.
+func CalcOptimismPVGWithEthClient(
+	rpc *rpc.Client,
+	chainID *big.Int,
+	ep common.Address,
+) gas.CalcPreVerificationGasFunc {
+	calc := gas.CalcOptimismPVGWithEthClient(rpc, chainID, ep)
+	return func(op *userop.UserOperation, static *big.Int) (*big.Int, error) {
+		if op.MaxFeePerGas.Sign() <= 0 {
+			return nil, rpcerrors.InvalidParams(errZeroL2Price)
+		}
+		return calc(op, static)
+	}
+}
@@ -0,0 +1,102 @@
+package estimator
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+)
+
+// The parameters below are the same as gas.NewDefaultOverhead so that the static preVerificationGas of a v0.6
+// and v0.7 op only differs by their encoded size.
+const (
+	intrinsicFixed      = 21000
+	perUserOpFixed      = 22874
+	perUserOpMultiplier = 25
+	zeroByteCost        = 4
+	nonZeroByteCost     = 16
+)
+
+var (
+	sanitizedPVG = big.NewInt(100000)
+	sanitizedVGL = big.NewInt(1000000)
+	sanitizedCGL = big.NewInt(1000000)
+)
+
+// CalcPreVerificationGasV07Func is the EntryPoint v0.7 equivalent of gas.CalcPreVerificationGasFunc. The
+// EntryPoint is passed in since the L1 component of the gas depends on the handleOps transaction.
+type CalcPreVerificationGasV07Func = func(
+	op *packedop.UserOperation,
+	ep common.Address,
+	static *big.Int,
+) (*big.Int, error)
+
+// OverheadV07 is the EntryPoint v0.7 equivalent of gas.Overhead. The preVerificationGas is calculated from
+// the packed encoding of the op which is what a bundler pays calldata costs for.
+type OverheadV07 struct {
+	calcPVGFunc     CalcPreVerificationGasV07Func
+	pvgBufferFactor int64
+}
+
+// NewDefaultOverheadV07 returns an OverheadV07 using parameters defined by the Ethereum protocol.
+func NewDefaultOverheadV07() *OverheadV07 {
+	return &OverheadV07{}
+}
+
+// SetCalcPreVerificationGasFunc sets a custom function to calculate preVerificationGas for networks that
+// have different models for gas.
+func (ov *OverheadV07) SetCalcPreVerificationGasFunc(fn CalcPreVerificationGasV07Func) {
+	ov.calcPVGFunc = fn
+}
+
+// SetPreVerificationGasBufferFactor sets the percentage to increase the preVerificationGas by. This is the
+// same as gas.Overhead.SetPreVerificationGasBufferFactor.
+func (ov *OverheadV07) SetPreVerificationGasBufferFactor(factor int64) {
+	ov.pvgBufferFactor = factor
+}
+
+// CalcPreVerificationGas returns an expected gas cost for processing the op from a batch sent to the given
+// EntryPoint.
+func (ov *OverheadV07) CalcPreVerificationGas(
+	op *packedop.UserOperation,
+	ep common.Address,
+) (*big.Int, error) {
+	// Sanitize fields to reduce as much variability due to length and zero bytes.
+	tmp := *op
+	tmp.PreVerificationGas = sanitizedPVG
+	tmp.VerificationGasLimit = sanitizedVGL
+	tmp.CallGasLimit = sanitizedCGL
+	tmp.Signature = bytes.Repeat([]byte{1}, len(op.Signature))
+	enc := tmp.Encode()
+
+	// The batch overhead is the intrinsic gas plus the calldata cost of the op. The per op overhead is
+	// correlated to the number of 32 byte words in the op.
+	cost := int64(intrinsicFixed)
+	for _, b := range enc {
+		if b == 0 {
+			cost += zeroByteCost
+		} else {
+			cost += nonZeroByteCost
+		}
+	}
+	cost += perUserOpMultiplier*int64((len(enc)+31)/32) + perUserOpFixed
+	static := big.NewInt(cost)
+
+	if ov.calcPVGFunc == nil {
+		return static, nil
+	}
+	return ov.calcPVGFunc(&tmp, ep, static)
+}
+
+// CalcPreVerificationGasWithBuffer returns CalcPreVerificationGas increased by the set buffer factor.
+func (ov *OverheadV07) CalcPreVerificationGasWithBuffer(
+	op *packedop.UserOperation,
+	ep common.Address,
+) (*big.Int, error) {
+	pvg, err := ov.CalcPreVerificationGas(op, ep)
+	if err != nil {
+		return nil, err
+	}
+	return withBuffer(pvg, &ov.pvgBufferFactor), nil
+}
@@ -0,0 +1,136 @@
+package estimator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stackup-wallet/stackup-bundler/pkg/arbitrum/nodeinterface"
+	"github.com/stackup-wallet/stackup-bundler/pkg/optimism/gaspriceoracle"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+// errZeroL2Price is returned on OP Stack networks if the L2 gas price of the op is 0, since the L1 fee can't
+// be converted to gas.
+var errZeroL2Price = errors.New("maxFeePerGas: must be greater than 0 to cover the L1 fee")
+
+// encodeHandleOps returns the calldata of a handleOps call with only the given op.
+func encodeHandleOps(op *packedop.UserOperation, beneficiary common.Address) ([]byte, error) {
+	args, err := packedop.HandleOpsMethod.Inputs.Pack([]contract.PackedUserOperation{op.Pack()}, beneficiary)
+	if err != nil {
+		return nil, err
+	}
+	return append(common.CopyBytes(packedop.HandleOpsMethod.ID), args...), nil
+}
+
+// CalcArbitrumPVGV07WithEthClient is the EntryPoint v0.7 equivalent of gas.CalcArbitrumPVGWithEthClient.
+func CalcArbitrumPVGV07WithEthClient(rpc *rpc.Client) CalcPreVerificationGasV07Func {
+	pk, _ := crypto.GenerateKey()
+	beneficiary := crypto.PubkeyToAddress(pk.PublicKey)
+	return func(op *packedop.UserOperation, ep common.Address, static *big.Int) (*big.Int, error) {
+		// Sanitize paymasterData in the same way as v0.6.
+		tmp := *op
+		tmp.PaymasterData = bytes.Repeat([]byte{1}, len(op.PaymasterData))
+		ho, err := encodeHandleOps(&tmp, beneficiary)
+		if err != nil {
+			return nil, err
+		}
+
+		ge, err := nodeinterface.GasEstimateL1ComponentMethod.Inputs.Pack(ep, tmp.Nonce.Sign() == 0, ho)
+		if err != nil {
+			return nil, err
+		}
+		req := map[string]any{
+			"from": common.HexToAddress("0x"),
+			"to":   nodeinterface.PrecompileAddress,
+			"data": hexutil.Encode(append(nodeinterface.GasEstimateL1ComponentMethod.ID, ge...)),
+		}
+		var out any
+		if err := rpc.Call(&out, "eth_call", &req, "latest"); err != nil {
+			return nil, err
+		}
+
+		gas, err := nodeinterface.DecodeGasEstimateL1ComponentOutput(out)
+		if err != nil {
+			return nil, err
+		}
+		return big.NewInt(0).Add(static, big.NewInt(0).SetUint64(gas.GasEstimateForL1)), nil
+	}
+}
+
+// CalcOptimismPVGV07WithEthClient is the EntryPoint v0.7 equivalent of gas.CalcOptimismPVGWithEthClient.
+func CalcOptimismPVGV07WithEthClient(rpc *rpc.Client, chainID *big.Int) CalcPreVerificationGasV07Func {
+	pk, _ := crypto.GenerateKey()
+	beneficiary := crypto.PubkeyToAddress(pk.PublicKey)
+	return func(op *packedop.UserOperation, ep common.Address, static *big.Int) (*big.Int, error) {
+		eth := ethclient.NewClient(rpc)
+		head, err := eth.HeaderByNumber(context.Background(), nil)
+		if err != nil {
+			return nil, err
+		}
+		tip, err := eth.SuggestGasTipCap(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		// Create a raw handleOps transaction.
+		ho, err := encodeHandleOps(op, beneficiary)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := types.SignNewTx(pk, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+			ChainID:   chainID,
+			GasTipCap: tip,
+			GasFeeCap: big.NewInt(0).Add(head.BaseFee, tip),
+			Gas:       math.MaxUint64,
+			To:        &ep,
+			Data:      ho,
+		})
+		if err != nil {
+			return nil, err
+		}
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		ge, err := gaspriceoracle.GetL1FeeMethod.Inputs.Pack(raw)
+		if err != nil {
+			return nil, err
+		}
+		req := map[string]any{
+			"from": common.HexToAddress("0x"),
+			"to":   gaspriceoracle.PrecompileAddress,
+			"data": hexutil.Encode(append(gaspriceoracle.GetL1FeeMethod.ID, ge...)),
+		}
+		var out any
+		if err := rpc.Call(&out, "eth_call", &req, "latest"); err != nil {
+			return nil, err
+		}
+
+		// The L1 buffer is equal to L1Fee/L2Price.
+		l1fee, err := gaspriceoracle.DecodeGetL1FeeMethodOutput(out)
+		if err != nil {
+			return nil, err
+		}
+		l2price := op.MaxFeePerGas
+		l2priority := big.NewInt(0).Add(op.MaxPriorityFeePerGas, head.BaseFee)
+		if l2priority.Cmp(l2price) == -1 {
+			l2price = l2priority
+		}
+		if l2price.Sign() <= 0 {
+			return nil, rpcerrors.InvalidParams(errZeroL2Price)
+		}
+		return big.NewInt(0).Add(static, big.NewInt(0).Div(l1fee, l2price)), nil
+	}
+}
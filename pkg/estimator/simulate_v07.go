@@ -0,0 +1,227 @@
+package estimator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+// simulationCode replaces the code of a v0.7 EntryPoint during gas estimation. The v0.7 EntryPoint has no
+// simulateHandleOp so this is used to make the same calls as handleOps from the EntryPoint address. The
+// calldata is a list of calls, each encoded as [20 byte target][32 byte gas][32 byte length][data]. The
+// calls are made in order and the result of each is returned as [gasUsed][returndatasize][returndata word
+// 0]. If a call fails it reverts with [call index][returndata]. Results are kept below the copied calldata
+// at 0x200 so there can be at most 5 calls.
+var simulationCode = hexutil.MustDecode(
+	"0x600060005b36811015610074578060340135808260540161020037813560601c5a600060008461020060008689601401" +
+		"35f1905a9003855261005057606084046000523d600060203e3d6020016000fd5b503d836020015260008360400152" +
+		"3d6000846040013e016054019060600190610004565b506000f3",
+)
+
+const (
+	simDeploy = iota
+	simValidate
+	simExecute
+)
+
+type simCall struct {
+	kind   int
+	target common.Address
+	gas    *big.Int
+	data   []byte
+}
+
+type simResult struct {
+	gasUsed *big.Int
+	retSize int
+	ret     common.Hash
+}
+
+// simulationError returns the revert data of a failed simulation as the same errors that a bundler would
+// return from gas estimation.
+func simulationError(kind int, revert []byte) error {
+	switch kind {
+	case simDeploy:
+		return rpcerrors.New(
+			rpcerrors.CodeSimulationFailed,
+			errors.New("AA13 initCode failed or OOG"),
+			&rpcerrors.SimulationData{Reason: "AA13 initCode failed or OOG", Revert: hexutil.Bytes(revert)},
+		)
+	case simValidate:
+		return rpcerrors.New(
+			rpcerrors.CodeSimulationFailed,
+			errors.New("AA23 reverted (or OOG)"),
+			&rpcerrors.SimulationData{Reason: "AA23 reverted (or OOG)", Revert: hexutil.Bytes(revert)},
+		)
+	default:
+		return rpcerrors.New(
+			rpcerrors.CodeExecutionReverted,
+			errors.New("execution reverted"),
+			&rpcerrors.SimulationData{Reason: "execution reverted", Revert: hexutil.Bytes(revert)},
+		)
+	}
+}
+
+// simulateV07 makes each call from the EntryPoint address with simulationCode and returns the gas used by
+// each.
+func simulateV07(
+	rpc *rpc.Client,
+	ep common.Address,
+	calls []*simCall,
+	maxGas *big.Int,
+) ([]*simResult, error) {
+	input := []byte{}
+	for _, c := range calls {
+		input = append(input, c.target.Bytes()...)
+		input = append(input, common.BigToHash(c.gas).Bytes()...)
+		input = append(input, common.BigToHash(big.NewInt(int64(len(c.data)))).Bytes()...)
+		input = append(input, c.data...)
+	}
+
+	req := map[string]any{
+		"from": common.HexToAddress("0x"),
+		"to":   ep,
+		"gas":  hexutil.EncodeBig(maxGas),
+		"data": hexutil.Encode(input),
+	}
+	override := map[common.Address]map[string]any{
+		ep: {"code": hexutil.Encode(simulationCode)},
+	}
+	var out hexutil.Bytes
+	if err := rpc.Call(&out, "eth_call", &req, "latest", &override); err != nil {
+		revert, ok := revertData(err)
+		if !ok {
+			return nil, err
+		}
+		if len(revert) < 32 {
+			return nil, fmt.Errorf("simulation: unexpected revert %s", hexutil.Encode(revert))
+		}
+		i := big.NewInt(0).SetBytes(revert[:32])
+		if !i.IsInt64() || i.Int64() >= int64(len(calls)) {
+			return nil, fmt.Errorf("simulation: unexpected revert %s", hexutil.Encode(revert))
+		}
+		return nil, simulationError(calls[i.Int64()].kind, revert[32:])
+	}
+	if len(out) != 96*len(calls) {
+		return nil, fmt.Errorf("simulation: unexpected result %s", out.String())
+	}
+
+	res := []*simResult{}
+	for i := 0; i < len(out); i += 96 {
+		res = append(res, &simResult{
+			gasUsed: big.NewInt(0).SetBytes(out[i : i+32]),
+			retSize: int(big.NewInt(0).SetBytes(out[i+32 : i+64]).Int64()),
+			ret:     common.BytesToHash(out[i+64 : i+96]),
+		})
+	}
+	return res, nil
+}
+
+// revertData returns the data of a reverted eth_call. The second value is false if err is not a revert.
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) || dataErr.ErrorData() == nil {
+		return nil, false
+	}
+	hex, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(hex)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// newSimCalls returns the calls that the EntryPoint makes to the SenderCreator and the account for the op
+// during handleOps. The gas for each call is taken from gas, in the same order as the calls.
+func newSimCalls(
+	op *packedop.UserOperation,
+	ep common.Address,
+	chainID *big.Int,
+	gas func(kind int) *big.Int,
+) ([]*simCall, error) {
+	packed := op.Pack()
+	hash := op.GetUserOpHash(ep, chainID)
+	calls := []*simCall{}
+
+	if op.Factory != (common.Address{}) {
+		args, err := packedop.CreateSenderMethod.Inputs.Pack(op.GetInitCode())
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, &simCall{
+			kind: simDeploy,
+			// The SenderCreator is the first contract created by the EntryPoint constructor.
+			target: crypto.CreateAddress(ep, 1),
+			gas:    gas(simDeploy),
+			data:   append(common.CopyBytes(packedop.CreateSenderMethod.ID), args...),
+		})
+	}
+
+	args, err := packedop.ValidateUserOpMethod.Inputs.Pack(packed, hash, big.NewInt(0))
+	if err != nil {
+		return nil, err
+	}
+	calls = append(calls, &simCall{
+		kind:   simValidate,
+		target: op.Sender,
+		gas:    gas(simValidate),
+		data:   append(common.CopyBytes(packedop.ValidateUserOpMethod.ID), args...),
+	})
+
+	if len(op.CallData) > 0 {
+		data := op.CallData
+		if bytes.HasPrefix(data, packedop.ExecuteUserOpMethod.ID) {
+			args, err := packedop.ExecuteUserOpMethod.Inputs.Pack(packed, hash)
+			if err != nil {
+				return nil, err
+			}
+			data = append(common.CopyBytes(packedop.ExecuteUserOpMethod.ID), args...)
+		}
+		calls = append(calls, &simCall{
+			kind:   simExecute,
+			target: op.Sender,
+			gas:    gas(simExecute),
+			data:   data,
+		})
+	}
+	return calls, nil
+}
+
+// checkSimResults returns an error if the deployment or validation results are not what the EntryPoint
+// expects.
+func checkSimResults(op *packedop.UserOperation, calls []*simCall, res []*simResult) error {
+	for i, c := range calls {
+		switch c.kind {
+		case simDeploy:
+			if common.BytesToAddress(res[i].ret.Bytes()) != op.Sender {
+				return rpcerrors.New(
+					rpcerrors.CodeSimulationFailed,
+					errors.New("AA14 initCode must return sender"),
+					&rpcerrors.SimulationData{Reason: "AA14 initCode must return sender"},
+				)
+			}
+		case simValidate:
+			if res[i].retSize < 32 && op.Factory == (common.Address{}) {
+				return rpcerrors.New(
+					rpcerrors.CodeSimulationFailed,
+					errors.New("AA20 account not deployed"),
+					&rpcerrors.SimulationData{Reason: "AA20 account not deployed"},
+				)
+			} else if res[i].retSize < 32 {
+				return simulationError(simValidate, nil)
+			}
+		}
+	}
+	return nil
+}
@@ -1,17 +1,20 @@
 package estimator
 
-import "math/big"
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
 
 var (
-	// The maximum total gas limit for the entire UserOperation.
-	maxGasLimit = big.NewInt(18000000)
+	// The maximum total gas limit for the entire UserOperation if Limits.MaxTotalGas is not set.
+	defaultMaxGasLimit = big.NewInt(18000000)
 
-	// This is a placeholder paymasterAndData with the correct length and all non-zero bytes. It is required
-	// to calculate an acceptable preVerificationGas prior to paymaster approval. Once preVerificationGas is
-	// calculated and added to the op, we can generate the real paymasterAndData. Note that if we calculate
-	// the preVerificationGas of the op again (with the real paymasterAndData), it will return a lower value
-	// since the real paymasterAndData is a mix of zero and non-zero bytes. If we want the preVerificationGas
-	// calculation to be the same with both dummy and real paymasterAndData, we need to know how to generate a
-	// dummy with the correct ratio of zero to non-zero bytes ahead of time.
-	DummyPaymasterAndDataHex = "0x010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101"
+	// This is a placeholder for the paymaster signature. It is a well formed ECDSA signature that recovers to
+	// an arbitrary address so signature recovery on the paymaster will not revert during gas estimation. All
+	// bytes are non-zero since a real signature is unlikely to contain any zero bytes.
+	dummySignature = hexutil.MustDecode(
+		"0x4fa2336274dcb4af1c395068527c44df3d1551a061cb54041a9db47ddf62a792" +
+			"2f7339727a83cb3ac6a0a774220e39212484d51a8e4757103313c42d50a7efda1b",
+	)
 )
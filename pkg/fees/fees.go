@@ -0,0 +1,159 @@
+// Package fees suggests maxFeePerGas and maxPriorityFeePerGas for a UserOperation so that the paymaster can
+// sign an op that is ready to be sent without further changes by the client.
+package fees
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Strategy is the method used to suggest fees.
+type Strategy string
+
+const (
+	// FeeHistory uses a percentile of priority fees paid in recent blocks from eth_feeHistory.
+	FeeHistory Strategy = "fee_history"
+
+	// Node uses the priority fee suggested by eth_maxPriorityFeePerGas. This works well for networks where the
+	// node is aware of chain specific pricing, such as OP stack chains.
+	Node Strategy = "node"
+
+	// Arbitrum sets the priority fee to zero since it is not used by the Arbitrum sequencer.
+	Arbitrum Strategy = "arbitrum"
+)
+
+// ParseStrategy returns the Strategy for the given string.
+func ParseStrategy(s string) (Strategy, error) {
+	switch st := Strategy(s); st {
+	case FeeHistory, Node, Arbitrum:
+		return st, nil
+	default:
+		return "", fmt.Errorf("fees: unknown strategy %s", s)
+	}
+}
+
+// Opts are the parameters used to suggest fees.
+type Opts struct {
+	// Blocks is the number of recent blocks used by the FeeHistory strategy.
+	Blocks uint64
+
+	// Percentile of priority fees in each block used by the FeeHistory strategy.
+	Percentile float64
+
+	// BaseFeeBufferPercent is added on top of the latest base fee to allow the op to remain valid if the base
+	// fee increases before it is included. A value of 100 will double the base fee.
+	BaseFeeBufferPercent int64
+}
+
+// Fees are the suggested fee fields for a UserOperation.
+type Fees struct {
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// Suggester computes fees from recent blocks using a Strategy.
+type Suggester struct {
+	eth      *ethclient.Client
+	strategy Strategy
+	opts     *Opts
+}
+
+// New returns a Suggester for the given strategy.
+func New(eth *ethclient.Client, strategy Strategy, opts *Opts) *Suggester {
+	return &Suggester{
+		eth:      eth,
+		strategy: strategy,
+		opts:     opts,
+	}
+}
+
+func (s *Suggester) withBuffer(baseFee *big.Int) *big.Int {
+	buf := big.NewInt(0).Mul(baseFee, big.NewInt(100+s.opts.BaseFeeBufferPercent))
+	return buf.Div(buf, big.NewInt(100))
+}
+
+// legacy returns the gas price for both fields on networks without a base fee.
+func (s *Suggester) legacy(ctx context.Context) (*Fees, error) {
+	gp, err := s.eth.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Fees{MaxFeePerGas: gp, MaxPriorityFeePerGas: gp}, nil
+}
+
+func (s *Suggester) fromFeeHistory(ctx context.Context) (*Fees, error) {
+	fh, err := s.eth.FeeHistory(ctx, s.opts.Blocks, nil, []float64{s.opts.Percentile})
+	if err != nil {
+		return nil, err
+	}
+
+	// The last base fee is for the next block.
+	baseFee := big.NewInt(0)
+	if len(fh.BaseFee) > 0 {
+		baseFee = fh.BaseFee[len(fh.BaseFee)-1]
+	}
+	if baseFee.Sign() == 0 {
+		return s.legacy(ctx)
+	}
+
+	rewards := []*big.Int{}
+	for _, r := range fh.Reward {
+		if len(r) > 0 && r[0] != nil {
+			rewards = append(rewards, r[0])
+		}
+	}
+	tip := big.NewInt(0)
+	if len(rewards) > 0 {
+		sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+		tip = rewards[len(rewards)/2]
+	}
+
+	return &Fees{
+		MaxFeePerGas:         big.NewInt(0).Add(s.withBuffer(baseFee), tip),
+		MaxPriorityFeePerGas: tip,
+	}, nil
+}
+
+func (s *Suggester) fromNode(ctx context.Context, useTip bool) (*Fees, error) {
+	head, err := s.eth.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if head.BaseFee == nil || head.BaseFee.Sign() == 0 {
+		return s.legacy(ctx)
+	}
+
+	tip := big.NewInt(0)
+	if useTip {
+		tip, err = s.eth.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Fees{
+		MaxFeePerGas:         big.NewInt(0).Add(s.withBuffer(head.BaseFee), tip),
+		MaxPriorityFeePerGas: tip,
+	}, nil
+}
+
+// Suggest returns the fees for an op to be included in a timely manner. A nil Suggester will return nil
+// fees and the op should be left as is.
+func (s *Suggester) Suggest(ctx context.Context) (*Fees, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	switch s.strategy {
+	case FeeHistory:
+		return s.fromFeeHistory(ctx)
+	case Arbitrum:
+		return s.fromNode(ctx, false)
+	default:
+		return s.fromNode(ctx, true)
+	}
+}
@@ -29,6 +29,11 @@ func decodeCtxTypes(
 		return common.HexToAddress(data.(string)), nil
 	}
 
+	// Hex string to uint64 conversion
+	if f == reflect.String && t == reflect.Uint64 {
+		return hexutil.DecodeUint64(data.(string))
+	}
+
 	// String to []byte conversion
 	if f == reflect.String && t == reflect.Slice {
 		byteStr := data.(string)
@@ -55,11 +60,11 @@ func validateAddressType(field reflect.Value) interface{} {
 	return field
 }
 
-func decodeMap(data map[string]any, ctx any) error {
+func decodeMap(data map[string]any, ctx any, errorUnset bool) error {
 	config := &mapstructure.DecoderConfig{
 		DecodeHook: decodeCtxTypes,
 		Result:     ctx,
-		ErrorUnset: true,
+		ErrorUnset: errorUnset,
 		MatchName:  exactFieldMatch,
 	}
 	decoder, err := mapstructure.NewDecoder(config)
@@ -86,7 +91,51 @@ type ContextType struct {
 
 func NewContextType(data map[string]any) (*ContextType, error) {
 	var ctx ContextType
-	if err := decodeMap(data, &ctx); err != nil {
+	if err := decodeMap(data, &ctx, true); err != nil {
+		return nil, err
+	}
+
+	if err := validateStruct(&ctx); err != nil {
+		return nil, err
+	}
+
+	return &ctx, nil
+}
+
+type ERC20Context struct {
+	Type  string         `json:"type"  mapstructure:"type"  validate:"required"`
+	Token common.Address `json:"token" mapstructure:"token" validate:"required"`
+}
+
+func NewERC20Context(data map[string]any) (*ERC20Context, error) {
+	var ctx ERC20Context
+	if err := decodeMap(data, &ctx, true); err != nil {
+		return nil, err
+	}
+
+	if err := validateStruct(&ctx); err != nil {
+		return nil, err
+	}
+
+	return &ctx, nil
+}
+
+// ContextOptions are optional fields that can be set for any context type. ValidAfter and ValidUntil can be
+// given as a number or a hex encoded quantity.
+type ContextOptions struct {
+	PolicyID   string         `json:"policyId"   mapstructure:"policyId"`
+	ValidAfter uint64         `json:"validAfter" mapstructure:"validAfter"`
+	ValidUntil uint64         `json:"validUntil" mapstructure:"validUntil"`
+	Paymaster  common.Address `json:"paymaster"  mapstructure:"paymaster"`
+
+	// SuggestFees will replace the fees on the op with ones suggested by the paymaster. Fees are also
+	// suggested if maxFeePerGas is zero.
+	SuggestFees bool `json:"suggestFees" mapstructure:"suggestFees"`
+}
+
+func NewContextOptions(data map[string]any) (*ContextOptions, error) {
+	var ctx ContextOptions
+	if err := decodeMap(data, &ctx, false); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,90 @@
+package erc20
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
+)
+
+// GetPaymasterStubData returns placeholder paymaster fields for gas estimation as defined in ERC-7677.
+func (h *Handler) GetPaymasterStubData(
+	pm common.Address,
+	token common.Address,
+	val *handlers.Validity,
+	v07 bool,
+) (*handlers.GetPaymasterStubDataResponse, error) {
+	data, err := h.newData(pm, token, val)
+	if err != nil {
+		return nil, err
+	}
+
+	return handlers.NewStubDataResponse(
+		data,
+		v07,
+		h.pmGas.VerificationGasLimit,
+		h.pmGas.PostOpGasLimit,
+	)
+}
+
+// GetPaymasterData returns a signed paymasterAndData for the op as defined in ERC-7677. Unlike Run, the gas
+// values of the op are expected to be final and are left unchanged. They must still be within the same gas
+// limits that Run would enforce.
+func (h *Handler) GetPaymasterData(
+	op *userop.UserOperation,
+	pm common.Address,
+	token common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
+) (*handlers.SponsorUserOperationResponse, error) {
+	data, err := h.newData(pm, token, val)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.gasEstimator.CheckOpGasLimits(op, pol.GasLimits()); err != nil {
+		return nil, err
+	}
+
+	// Check op against the sponsorship policy. The paymaster address is enough to account for the
+	// paymasterAndData in the max gas cost.
+	pmOp := *op
+	pmOp.PaymasterAndData = pm.Bytes()
+	if err := pol.Evaluate(&pmOp, pmOp.GetMaxPrefund()); err != nil {
+		return nil, err
+	}
+
+	return handlers.Sign(h.keys, op, h.chainID, data)
+}
+
+// GetPaymasterDataV07 is the EntryPoint v0.7 equivalent of GetPaymasterData. The paymaster gas limits on
+// the op are signed as is and must be at least the values returned from GetPaymasterStubData.
+func (h *Handler) GetPaymasterDataV07(
+	op *packedop.UserOperation,
+	pm common.Address,
+	token common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
+) (*handlers.SponsorUserOperationResponse, error) {
+	data, err := h.newData(pm, token, val)
+	if err != nil {
+		return nil, err
+	}
+	err = handlers.CheckPaymasterGasLimits(op, h.pmGas.VerificationGasLimit, h.pmGas.PostOpGasLimit)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.gasEstimator.CheckOpGasLimitsV07(op, pol.GasLimits()); err != nil {
+		return nil, err
+	}
+	pmOp := *op
+	pmOp.Paymaster = pm
+
+	// Check op against the sponsorship policy.
+	if err := pol.Evaluate(pmOp.ToUserOperation(), pmOp.GetMaxPrefund()); err != nil {
+		return nil, err
+	}
+
+	return handlers.SignV07(h.keys, &pmOp, h.chainID, data)
+}
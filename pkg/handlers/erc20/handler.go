@@ -0,0 +1,198 @@
+package erc20
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/estimator"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/keyring"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/oracle"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+type Handler struct {
+	keys         *keyring.Keyring
+	rpc          *rpc.Client
+	eth          *ethclient.Client
+	chainID      *big.Int
+	gasEstimator *estimator.GasEstimator
+	pmGas        *handlers.PaymasterGasLimits
+	oracle       oracle.Oracle
+}
+
+func New(
+	keys *keyring.Keyring,
+	rpc *rpc.Client,
+	eth *ethclient.Client,
+	chain *big.Int,
+	ov estimator.Overheads,
+	ovV07 *estimator.OverheadV07,
+	pmGas *handlers.PaymasterGasLimits,
+	limits *estimator.Limits,
+	oracle oracle.Oracle,
+) *Handler {
+	return &Handler{
+		rpc:          rpc,
+		eth:          eth,
+		chainID:      chain,
+		keys:         keys,
+		gasEstimator: estimator.New(keys, rpc, eth, chain, ov, ovV07, limits),
+		pmGas:        pmGas,
+		oracle:       oracle,
+	}
+}
+
+// newData returns the paymaster data for the token at its current exchange rate.
+func (h *Handler) newData(
+	pm common.Address,
+	token common.Address,
+	val *handlers.Validity,
+) (*contract.Data, error) {
+	rate, err := h.getExchangeRate(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return contract.NewData(pm, token, rate, val.ValidAfter, val.ValidUntil), nil
+}
+
+// Estimate returns a copy of the op with the gas limits that Run would sign for and the exchange rate of
+// the token. The paymasterAndData on the returned op is a placeholder and must not be used on chain.
+func (h *Handler) Estimate(
+	op *userop.UserOperation,
+	ep common.Address,
+	pm common.Address,
+	token common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
+) (*userop.UserOperation, *big.Int, error) {
+	data, err := h.newData(pm, token, val)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pmOp, err := h.gasEstimator.OverrideOpGasLimitsForPND(op, ep, data, pol.GasLimits())
+	if err != nil {
+		return nil, nil, err
+	}
+	return pmOp, data.ExchangeRate, nil
+}
+
+// EstimateV07 is the EntryPoint v0.7 equivalent of Estimate.
+func (h *Handler) EstimateV07(
+	op *packedop.UserOperation,
+	ep common.Address,
+	pm common.Address,
+	token common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
+) (*packedop.UserOperation, *big.Int, error) {
+	data, err := h.newData(pm, token, val)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pmOp, err := h.gasEstimator.OverrideOpGasLimitsForPNDV07(
+		op,
+		ep,
+		data,
+		h.pmGas.VerificationGasLimit,
+		h.pmGas.PostOpGasLimit,
+		pol.GasLimits(),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pmOp, data.ExchangeRate, nil
+}
+
+// Run returns a paymasterAndData that will charge the sender for gas in the given ERC-20 token. The token
+// must have a price source in the oracle and the op must satisfy the given policy.
+func (h *Handler) Run(
+	op *userop.UserOperation,
+	ep common.Address,
+	pm common.Address,
+	token common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
+) (*handlers.SponsorUserOperationResponse, error) {
+	// Get paymaster data with the exchange rate for the token.
+	data, err := h.newData(pm, token, val)
+	if err != nil {
+		return nil, err
+	}
+
+	// Estimate gas values to account for paymasterAndData.
+	pmOp, err := h.gasEstimator.OverrideOpGasLimitsForPND(op, ep, data, pol.GasLimits())
+	if err != nil {
+		return nil, err
+	}
+
+	// Check op against the sponsorship policy.
+	if err := pol.Evaluate(pmOp, pmOp.GetMaxPrefund()); err != nil {
+		return nil, err
+	}
+
+	return handlers.Sign(h.keys, pmOp, h.chainID, data)
+}
+
+// RunV07 is the EntryPoint v0.7 equivalent of Run.
+func (h *Handler) RunV07(
+	op *packedop.UserOperation,
+	ep common.Address,
+	pm common.Address,
+	token common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
+) (*handlers.SponsorUserOperationResponse, error) {
+	// Get paymaster data with the exchange rate for the token.
+	data, err := h.newData(pm, token, val)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set paymaster gas limits and preVerificationGas.
+	pmOp, err := h.gasEstimator.OverrideOpGasLimitsForPNDV07(
+		op,
+		ep,
+		data,
+		h.pmGas.VerificationGasLimit,
+		h.pmGas.PostOpGasLimit,
+		pol.GasLimits(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check op against the sponsorship policy.
+	if err := pol.Evaluate(pmOp.ToUserOperation(), pmOp.GetMaxPrefund()); err != nil {
+		return nil, err
+	}
+
+	return handlers.SignV07(h.keys, pmOp, h.chainID, data)
+}
+
+// TokenData is the data field of the JSON-RPC error returned when an exchange rate is not available.
+type TokenData struct {
+	Token common.Address `json:"token"`
+}
+
+// getExchangeRate returns the exchange rate for the token from the oracle. If it is not available the
+// error will have a JSON-RPC code for whether the token is not supported or the oracle has failed.
+func (h *Handler) getExchangeRate(token common.Address) (*big.Int, error) {
+	rate, err := h.oracle.GetExchangeRate(token)
+	if errors.Is(err, oracle.ErrTokenNotSupported) {
+		return nil, rpcerrors.New(rpcerrors.CodeTokenNotSupported, err, &TokenData{Token: token})
+	} else if err != nil {
+		return nil, rpcerrors.New(rpcerrors.CodeOracleUnavailable, err, &TokenData{Token: token})
+	}
+	return rate, nil
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/estimator"
+)
+
+// GetPaymasterStubDataResponse is the result of pm_getPaymasterStubData as defined in ERC-7677. Only the
+// fields that match the EntryPoint version of the request are set.
+type GetPaymasterStubDataResponse struct {
+	PaymasterAndData              string `json:"paymasterAndData,omitempty"`
+	Paymaster                     string `json:"paymaster,omitempty"`
+	PaymasterData                 string `json:"paymasterData,omitempty"`
+	PaymasterVerificationGasLimit string `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       string `json:"paymasterPostOpGasLimit,omitempty"`
+	IsFinal                       bool   `json:"isFinal"`
+}
+
+// GetPaymasterDataResponse is the result of pm_getPaymasterData as defined in ERC-7677. Only the fields
+// that match the EntryPoint version of the request are set.
+type GetPaymasterDataResponse struct {
+	PaymasterAndData string `json:"paymasterAndData,omitempty"`
+	Paymaster        string `json:"paymaster,omitempty"`
+	PaymasterData    string `json:"paymasterData,omitempty"`
+}
+
+// NewStubDataResponse returns stub paymaster fields for the given data with a dummy signature. The stub is
+// encoded the same way as the signed fields so the paymaster's validation will not revert during gas
+// estimation and the wallet's preVerificationGas will not change once the real signature is added. For
+// EntryPoint v0.7 the paymaster gas limits are also returned so that wallets do not need to estimate them.
+func NewStubDataResponse(
+	data *contract.Data,
+	v07 bool,
+	pmVerificationGasLimit *big.Int,
+	pmPostOpGasLimit *big.Int,
+) (*GetPaymasterStubDataResponse, error) {
+	if !v07 {
+		pnd, err := estimator.DummyPaymasterAndData(data)
+		if err != nil {
+			return nil, err
+		}
+		return &GetPaymasterStubDataResponse{PaymasterAndData: hexutil.Encode(pnd)}, nil
+	}
+
+	pmData, err := estimator.DummyPaymasterData(data)
+	if err != nil {
+		return nil, err
+	}
+	return &GetPaymasterStubDataResponse{
+		Paymaster:                     data.Paymaster.Hex(),
+		PaymasterData:                 hexutil.Encode(pmData),
+		PaymasterVerificationGasLimit: hexutil.EncodeBig(pmVerificationGasLimit),
+		PaymasterPostOpGasLimit:       hexutil.EncodeBig(pmPostOpGasLimit),
+	}, nil
+}
+
+// NewPaymasterDataResponse returns the ERC-7677 representation of a sponsorship response.
+func NewPaymasterDataResponse(res *SponsorUserOperationResponse) *GetPaymasterDataResponse {
+	return &GetPaymasterDataResponse{
+		PaymasterAndData: res.PaymasterAndData,
+		Paymaster:        res.Paymaster,
+		PaymasterData:    res.PaymasterData,
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+)
+
+func TestNewStubDataResponseLength(t *testing.T) {
+	data := contract.NewData(
+		common.HexToAddress("0x0000000000000000000000000000000000000001"),
+		common.HexToAddress("0x0000000000000000000000000000000000000002"),
+		big.NewInt(1000000),
+		big.NewInt(1700000000),
+		big.NewInt(1700003600),
+	)
+	sig := make([]byte, 65)
+
+	pnd, err := contract.EncodePaymasterAndData(data, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub, err := NewStubDataResponse(data, false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(hexutil.MustDecode(stub.PaymasterAndData)); got != len(pnd) {
+		t.Errorf("paymasterAndData: got %d bytes, want %d", got, len(pnd))
+	}
+
+	pmData, err := contract.EncodePaymasterData(data, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub, err = NewStubDataResponse(data, true, big.NewInt(50000), big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(hexutil.MustDecode(stub.PaymasterData)); got != len(pmData) {
+		t.Errorf("paymasterData: got %d bytes, want %d", got, len(pmData))
+	}
+}
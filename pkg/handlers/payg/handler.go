@@ -4,75 +4,135 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/stackup-wallet/stackup-bundler/pkg/gas"
-	"github.com/stackup-wallet/stackup-bundler/pkg/signer"
 	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
 	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
 	"github.com/stackup-wallet/stackup-paymaster/pkg/estimator"
 	"github.com/stackup-wallet/stackup-paymaster/pkg/handlers"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/keyring"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
 )
 
 type Handler struct {
-	signer       *signer.EOA
+	keys         *keyring.Keyring
 	rpc          *rpc.Client
 	eth          *ethclient.Client
+	chainID      *big.Int
 	gasEstimator *estimator.GasEstimator
+	pmGas        *handlers.PaymasterGasLimits
 }
 
 func New(
-	signer *signer.EOA,
+	keys *keyring.Keyring,
 	rpc *rpc.Client,
 	eth *ethclient.Client,
 	chain *big.Int,
-	ov *gas.Overhead,
+	ov estimator.Overheads,
+	ovV07 *estimator.OverheadV07,
+	pmGas *handlers.PaymasterGasLimits,
+	limits *estimator.Limits,
 ) *Handler {
 	return &Handler{
 		rpc:          rpc,
 		eth:          eth,
-		signer:       signer,
-		gasEstimator: estimator.New(signer, rpc, eth, chain, ov),
+		chainID:      chain,
+		keys:         keys,
+		gasEstimator: estimator.New(keys, rpc, eth, chain, ov, ovV07, limits),
+		pmGas:        pmGas,
 	}
 }
 
+func newData(pm common.Address, val *handlers.Validity) *contract.Data {
+	return contract.NewData(
+		pm,
+		common.HexToAddress("0x"),
+		big.NewInt(0),
+		val.ValidAfter,
+		val.ValidUntil,
+	)
+}
+
+// Estimate returns a copy of the op with the gas limits that Run would sign for. The paymasterAndData on the
+// returned op is a placeholder and must not be used on chain.
+func (h *Handler) Estimate(
+	op *userop.UserOperation,
+	ep common.Address,
+	pm common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
+) (*userop.UserOperation, error) {
+	return h.gasEstimator.OverrideOpGasLimitsForPND(op, ep, newData(pm, val), pol.GasLimits())
+}
+
+// EstimateV07 is the EntryPoint v0.7 equivalent of Estimate.
+func (h *Handler) EstimateV07(
+	op *packedop.UserOperation,
+	ep common.Address,
+	pm common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
+) (*packedop.UserOperation, error) {
+	return h.gasEstimator.OverrideOpGasLimitsForPNDV07(
+		op,
+		ep,
+		newData(pm, val),
+		h.pmGas.VerificationGasLimit,
+		big.NewInt(0),
+		pol.GasLimits(),
+	)
+}
+
 func (h *Handler) Run(
 	op *userop.UserOperation,
 	ep common.Address,
 	pm common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
 ) (*handlers.SponsorUserOperationResponse, error) {
-	// Get paymaster data.
-	data := contract.NewData(pm, common.HexToAddress("0x"), big.NewInt(0))
-
 	// Estimate gas values to account for paymasterAndData.
-	pmOp, err := h.gasEstimator.OverrideOpGasLimitsForPND(op, ep, data)
+	data := newData(pm, val)
+	pmOp, err := h.gasEstimator.OverrideOpGasLimitsForPND(op, ep, data, pol.GasLimits())
 	if err != nil {
 		return nil, err
 	}
 
-	// Fetch hash.
-	hash, err := contract.GetHash(h.eth, pmOp, data)
-	if err != nil {
+	// Check op against the sponsorship policy.
+	if err := pol.Evaluate(pmOp, pmOp.GetMaxPrefund()); err != nil {
 		return nil, err
 	}
 
-	// Sign hash.
-	sig, err := contract.Sign(hash[:], h.signer)
+	return handlers.Sign(h.keys, pmOp, h.chainID, data)
+}
+
+// RunV07 is the EntryPoint v0.7 equivalent of Run. The paymaster does not require a postOp call for pay as
+// you go sponsorship so the paymasterPostOpGasLimit is always zero.
+func (h *Handler) RunV07(
+	op *packedop.UserOperation,
+	ep common.Address,
+	pm common.Address,
+	pol *policy.Policy,
+	val *handlers.Validity,
+) (*handlers.SponsorUserOperationResponse, error) {
+	// Set paymaster gas limits and preVerificationGas.
+	data := newData(pm, val)
+	pmOp, err := h.gasEstimator.OverrideOpGasLimitsForPNDV07(
+		op,
+		ep,
+		data,
+		h.pmGas.VerificationGasLimit,
+		big.NewInt(0),
+		pol.GasLimits(),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	// Encode final paymasterAndData.
-	pnd, err := contract.EncodePaymasterAndData(data, sig)
-	if err != nil {
+	// Check op against the sponsorship policy.
+	if err := pol.Evaluate(pmOp.ToUserOperation(), pmOp.GetMaxPrefund()); err != nil {
 		return nil, err
 	}
 
-	return &handlers.SponsorUserOperationResponse{
-		PaymasterAndData:     hexutil.Encode(pnd),
-		PreVerificationGas:   hexutil.EncodeBig(pmOp.PreVerificationGas),
-		VerificationGasLimit: hexutil.EncodeBig(pmOp.VerificationGasLimit),
-		CallGasLimit:         hexutil.EncodeBig(pmOp.CallGasLimit),
-	}, nil
+	return handlers.SignV07(h.keys, pmOp, h.chainID, data)
 }
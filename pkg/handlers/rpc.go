@@ -1,8 +1,129 @@
 package handlers
 
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/keyring"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
 type SponsorUserOperationResponse struct {
-	PaymasterAndData     string `json:"paymasterAndData"`
+	PaymasterAndData     string `json:"paymasterAndData,omitempty"`
 	PreVerificationGas   string `json:"preVerificationGas"`
 	VerificationGasLimit string `json:"verificationGasLimit"`
 	CallGasLimit         string `json:"callGasLimit"`
+
+	// Fields below are only set if fees were suggested by the paymaster and are included in the signed op.
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
+
+	// Fields below are only set for EntryPoint v0.7 and replace paymasterAndData.
+	Paymaster                     string `json:"paymaster,omitempty"`
+	PaymasterData                 string `json:"paymasterData,omitempty"`
+	PaymasterVerificationGasLimit string `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       string `json:"paymasterPostOpGasLimit,omitempty"`
+}
+
+// Sign returns a response with the gas values of the op and a paymasterAndData signed for the given data.
+func Sign(
+	keys *keyring.Keyring,
+	op *userop.UserOperation,
+	chainID *big.Int,
+	data *contract.Data,
+) (*SponsorUserOperationResponse, error) {
+	pnd, err := keys.SignPaymasterAndData(op, chainID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SponsorUserOperationResponse{
+		PaymasterAndData:     hexutil.Encode(pnd),
+		PreVerificationGas:   hexutil.EncodeBig(op.PreVerificationGas),
+		VerificationGasLimit: hexutil.EncodeBig(op.VerificationGasLimit),
+		CallGasLimit:         hexutil.EncodeBig(op.CallGasLimit),
+	}, nil
+}
+
+// SignV07 is the EntryPoint v0.7 equivalent of Sign. The paymaster fields of the op other than paymasterData
+// must already be set.
+func SignV07(
+	keys *keyring.Keyring,
+	op *packedop.UserOperation,
+	chainID *big.Int,
+	data *contract.Data,
+) (*SponsorUserOperationResponse, error) {
+	pmData, err := keys.SignPaymasterData(op, chainID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SponsorUserOperationResponse{
+		Paymaster:                     op.Paymaster.Hex(),
+		PaymasterData:                 hexutil.Encode(pmData),
+		PaymasterVerificationGasLimit: hexutil.EncodeBig(op.PaymasterVerificationGasLimit),
+		PaymasterPostOpGasLimit:       hexutil.EncodeBig(op.PaymasterPostOpGasLimit),
+		PreVerificationGas:            hexutil.EncodeBig(op.PreVerificationGas),
+		VerificationGasLimit:          hexutil.EncodeBig(op.VerificationGasLimit),
+		CallGasLimit:                  hexutil.EncodeBig(op.CallGasLimit),
+	}, nil
+}
+
+// QuoteResponse is the result of pm_quote. It has the gas limits and cost of sponsoring an op but no
+// paymaster data, so it can't be used to get an op included on chain.
+type QuoteResponse struct {
+	// Sponsored is false if the op would be rejected by a policy, budget, or deposit check. Reason and
+	// ReasonCode are the message and JSON-RPC error code that sponsorship would have failed with.
+	Sponsored  bool   `json:"sponsored"`
+	Reason     string `json:"reason,omitempty"`
+	ReasonCode int    `json:"reasonCode,omitempty"`
+
+	Paymaster                     string `json:"paymaster"`
+	PreVerificationGas            string `json:"preVerificationGas"`
+	VerificationGasLimit          string `json:"verificationGasLimit"`
+	CallGasLimit                  string `json:"callGasLimit"`
+	PaymasterVerificationGasLimit string `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       string `json:"paymasterPostOpGasLimit,omitempty"`
+	MaxFeePerGas                  string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas          string `json:"maxPriorityFeePerGas"`
+
+	// MaxCost is the max amount of wei the op can be charged for gas.
+	MaxCost string `json:"maxCost"`
+
+	// Fields below are only set for ERC-20 quotes. MaxTokenCost is MaxCost in the token's smallest unit at
+	// the current exchange rate.
+	Token        string `json:"token,omitempty"`
+	ExchangeRate string `json:"exchangeRate,omitempty"`
+	MaxTokenCost string `json:"maxTokenCost,omitempty"`
+}
+
+// PaymasterGasLimits are the gas limits set on the paymaster fields of an EntryPoint v0.7 op.
+type PaymasterGasLimits struct {
+	VerificationGasLimit *big.Int
+	PostOpGasLimit       *big.Int
+}
+
+// CheckPaymasterGasLimits returns an error if the paymaster gas limits on the op are lower than the given
+// limits, since the paymaster could run out of gas. Higher limits are allowed as the max cost of the op is
+// still bounded by the sponsorship policy.
+func CheckPaymasterGasLimits(
+	op *packedop.UserOperation,
+	verificationGasLimit *big.Int,
+	postOpGasLimit *big.Int,
+) error {
+	if op.PaymasterVerificationGasLimit.Cmp(verificationGasLimit) < 0 {
+		return rpcerrors.InvalidParams(
+			fmt.Errorf("paymasterVerificationGasLimit: must be at least %s", verificationGasLimit),
+		)
+	}
+	if op.PaymasterPostOpGasLimit.Cmp(postOpGasLimit) < 0 {
+		return rpcerrors.InvalidParams(
+			fmt.Errorf("paymasterPostOpGasLimit: must be at least %s", postOpGasLimit),
+		)
+	}
+	return nil
 }
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/stackup-wallet/stackup-paymaster/pkg/policy"
+)
+
+// Validity is the time window in which a signed paymasterAndData can be used.
+type Validity struct {
+	ValidAfter *big.Int
+	ValidUntil *big.Int
+}
+
+// ValidityConfig holds the server wide limits for validity windows.
+type ValidityConfig struct {
+	// ValidFor is the length of the window when the request does not specify a validUntil.
+	ValidFor time.Duration
+
+	// MaxValidFor is the max length of any window.
+	MaxValidFor time.Duration
+
+	// MaxValidAfterDelay is the max amount of time from now that a window can start.
+	MaxValidAfterDelay time.Duration
+}
+
+// NewValidity returns the validity window for a request. The policy can override the default and max
+// window length, but never beyond the server max. A validUntil requested in the context is clamped to the max
+// window length. A validAfter beyond the max delay is rejected since moving it earlier would approve the op
+// before the requested time.
+func (c *ValidityConfig) NewValidity(opts *ContextOptions, pol *policy.Policy) (*Validity, error) {
+	validFor, maxValidFor := c.ValidFor, c.MaxValidFor
+	if pol != nil && pol.ValidFor > 0 {
+		validFor = pol.ValidFor
+	}
+	if pol != nil && pol.MaxValidFor > 0 {
+		maxValidFor = min(pol.MaxValidFor, c.MaxValidFor)
+	}
+	validFor = min(validFor, maxValidFor)
+
+	now := time.Now()
+	start := now
+	validAfter := int64(0)
+	if opts.ValidAfter > 0 {
+		start = time.Unix(int64(opts.ValidAfter), 0)
+		if start.After(now.Add(c.MaxValidAfterDelay)) {
+			return nil, fmt.Errorf("validAfter: must be within %s of the current time", c.MaxValidAfterDelay)
+		}
+		validAfter = start.Unix()
+	}
+	if start.Before(now) {
+		start = now
+	}
+
+	end := start.Add(validFor)
+	if opts.ValidUntil > 0 {
+		end = time.Unix(int64(opts.ValidUntil), 0)
+		if end.After(start.Add(maxValidFor)) {
+			end = start.Add(maxValidFor)
+		}
+	}
+	if !end.After(now) || end.Unix() <= validAfter {
+		return nil, errors.New("validUntil: must be after validAfter and the current time")
+	}
+
+	return &Validity{
+		ValidAfter: big.NewInt(validAfter),
+		ValidUntil: big.NewInt(end.Unix()),
+	}, nil
+}
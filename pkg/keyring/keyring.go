@@ -0,0 +1,139 @@
+// Package keyring selects the verifier key to sign with based on the on-chain state of each paymaster.
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-logr/logr"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/signer"
+)
+
+// ErrNoVerifierKey is returned when none of the keys match the current verifier of a paymaster.
+var ErrNoVerifierKey = errors.New("keyring: no key for paymaster verifier")
+
+// NoVerifierKeyData is the data field of the JSON-RPC error returned with ErrNoVerifierKey.
+type NoVerifierKeyData struct {
+	Paymaster common.Address `json:"paymaster"`
+	Verifier  common.Address `json:"verifier"`
+}
+
+// Keyring holds several verifier keys and signs with the one that matches the current verifier() of each
+// paymaster. This allows the verifier to be rotated on chain without restarting the service.
+type Keyring struct {
+	eth     *ethclient.Client
+	signers map[common.Address]signer.Signer
+	logger  logr.Logger
+
+	mu        sync.RWMutex
+	verifiers map[common.Address]common.Address
+}
+
+// New returns a Keyring with the given signers.
+func New(eth *ethclient.Client, signers []signer.Signer, l logr.Logger) *Keyring {
+	m := make(map[common.Address]signer.Signer, len(signers))
+	for _, s := range signers {
+		m[s.Address()] = s
+	}
+
+	return &Keyring{
+		eth:       eth,
+		signers:   m,
+		logger:    l,
+		verifiers: make(map[common.Address]common.Address),
+	}
+}
+
+func (k *Keyring) fetchVerifier(pm common.Address) (common.Address, error) {
+	c, err := contract.NewContract(pm, k.eth)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return c.Verifier(&bind.CallOpts{})
+}
+
+// Refresh fetches the current verifier of each paymaster and logs any changes. A paymaster that fails to
+// refresh does not stop the others and all errors are returned together.
+func (k *Keyring) Refresh(pms []common.Address) error {
+	var errs []error
+	for _, pm := range pms {
+		v, err := k.fetchVerifier(pm)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pm.Hex(), err))
+			continue
+		}
+
+		k.mu.Lock()
+		prev, ok := k.verifiers[pm]
+		k.verifiers[pm] = v
+		k.mu.Unlock()
+
+		if !ok || prev != v {
+			_, known := k.signers[v]
+			k.logger.Info(
+				"paymaster verifier updated",
+				"paymaster", pm.Hex(),
+				"verifier", v.Hex(),
+				"has_key", known,
+			)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Watch refreshes the verifiers of the given paymasters at an interval in a separate goroutine.
+func (k *Keyring) Watch(pms []common.Address, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := k.Refresh(pms); err != nil {
+				k.logger.Error(err, "keyring refresh error")
+			}
+		}
+	}()
+}
+
+// Get returns the Signer for the current verifier of the paymaster. If the verifier is not yet known, it
+// is fetched from the chain.
+func (k *Keyring) Get(pm common.Address) (signer.Signer, error) {
+	k.mu.RLock()
+	v, ok := k.verifiers[pm]
+	k.mu.RUnlock()
+
+	if !ok {
+		if err := k.Refresh([]common.Address{pm}); err != nil {
+			return nil, err
+		}
+		k.mu.RLock()
+		v = k.verifiers[pm]
+		k.mu.RUnlock()
+	}
+
+	s, ok := k.signers[v]
+	if !ok {
+		return nil, rpcerrors.New(
+			rpcerrors.CodeNoVerifierKey,
+			fmt.Errorf("%w: %s has verifier %s", ErrNoVerifierKey, pm.Hex(), v.Hex()),
+			&NoVerifierKeyData{Paymaster: pm, Verifier: v},
+		)
+	}
+	return s, nil
+}
+
+// Sign signs the message with the key of the paymaster's current verifier.
+func (k *Keyring) Sign(pm common.Address, message []byte) ([]byte, error) {
+	s, err := k.Get(pm)
+	if err != nil {
+		return nil, err
+	}
+	return s.SignMessage(message)
+}
@@ -0,0 +1,50 @@
+package keyring
+
+import (
+	"math/big"
+
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+)
+
+// SignPaymasterAndData returns the paymasterAndData of a v0.6 op for the given data, signed by the verifier of
+// the paymaster. All other fields of the op must be final since they are included in the hash.
+func (k *Keyring) SignPaymasterAndData(
+	op *userop.UserOperation,
+	chainID *big.Int,
+	data *contract.Data,
+) ([]byte, error) {
+	hash, err := contract.ComputeHash(op, chainID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := k.Sign(data.Paymaster, hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return contract.EncodePaymasterAndData(data, sig)
+}
+
+// SignPaymasterData is the EntryPoint v0.7 equivalent of SignPaymasterAndData. It returns only the
+// paymasterData since the paymaster address and gas limits are separate fields on the op and must already be
+// set.
+func (k *Keyring) SignPaymasterData(
+	op *packedop.UserOperation,
+	chainID *big.Int,
+	data *contract.Data,
+) ([]byte, error) {
+	hash, err := contract.ComputeHashV07(op.Pack(), chainID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := k.Sign(data.Paymaster, hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return contract.EncodePaymasterData(data, sig)
+}
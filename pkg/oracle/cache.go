@@ -0,0 +1,51 @@
+package oracle
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type cacheEntry struct {
+	rate      *big.Int
+	expiresAt time.Time
+}
+
+type cache struct {
+	oracle Oracle
+	ttl    time.Duration
+	mu     sync.Mutex
+	rates  map[common.Address]*cacheEntry
+}
+
+// WithCache returns an Oracle that stores exchange rates from the given Oracle for the duration of ttl
+// before fetching them again.
+func WithCache(o Oracle, ttl time.Duration) Oracle {
+	return &cache{
+		oracle: o,
+		ttl:    ttl,
+		rates:  map[common.Address]*cacheEntry{},
+	}
+}
+
+// GetExchangeRate implements the Oracle interface.
+func (c *cache) GetExchangeRate(token common.Address) (*big.Int, error) {
+	c.mu.Lock()
+	entry, ok := c.rates[token]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return big.NewInt(0).Set(entry.rate), nil
+	}
+
+	rate, err := c.oracle.GetExchangeRate(token)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.rates[token] = &cacheEntry{rate: rate, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return big.NewInt(0).Set(rate), nil
+}
@@ -0,0 +1,111 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+type roundData struct {
+	answer    *big.Int
+	decimals  uint8
+	updatedAt time.Time
+}
+
+// Chainlink is an Oracle that reads prices from Chainlink-style aggregators.
+type Chainlink struct {
+	eth          *ethclient.Client
+	tokenFeed    common.Address
+	nativeFeed   common.Address
+	maxStaleness time.Duration
+}
+
+// NewChainlink returns an Oracle that reads the token price from an AggregatorV3Interface. If nativeFeed is
+// the zero address, tokenFeed must quote the token in the chain's native currency (e.g. USDC / ETH).
+// Otherwise, both feeds must quote in the same currency (e.g. USDC / USD and ETH / USD) and the rate is
+// derived from the two. Answers older than maxStaleness are rejected.
+func NewChainlink(
+	eth *ethclient.Client,
+	tokenFeed common.Address,
+	nativeFeed common.Address,
+	maxStaleness time.Duration,
+) *Chainlink {
+	return &Chainlink{
+		eth:          eth,
+		tokenFeed:    tokenFeed,
+		nativeFeed:   nativeFeed,
+		maxStaleness: maxStaleness,
+	}
+}
+
+func (c *Chainlink) latestRoundData(feed common.Address) (*roundData, error) {
+	agg := bind.NewBoundContract(feed, aggregatorV3, c.eth, c.eth, c.eth)
+
+	var dec []any
+	if err := agg.Call(nil, &dec, "decimals"); err != nil {
+		return nil, err
+	}
+	var round []any
+	if err := agg.Call(nil, &round, "latestRoundData"); err != nil {
+		return nil, err
+	}
+
+	answer := round[1].(*big.Int)
+	if answer.Sign() <= 0 {
+		return nil, fmt.Errorf("oracle: invalid answer %s from feed %s", answer, feed.Hex())
+	}
+	updatedAt := time.Unix(round[3].(*big.Int).Int64(), 0)
+	if time.Since(updatedAt) > c.maxStaleness {
+		return nil, fmt.Errorf("%w: feed %s last updated at %s", ErrStaleAnswer, feed.Hex(), updatedAt)
+	}
+
+	return &roundData{
+		answer:    answer,
+		decimals:  dec[0].(uint8),
+		updatedAt: updatedAt,
+	}, nil
+}
+
+// GetExchangeRate implements the Oracle interface.
+func (c *Chainlink) GetExchangeRate(token common.Address) (*big.Int, error) {
+	tokenDecimals, err := getTokenDecimals(c.eth, token)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenPrice, err := c.latestRoundData(c.tokenFeed)
+	if err != nil {
+		return nil, err
+	}
+
+	// rate = 10^tokenDecimals * nativePrice / tokenPrice, with both prices normalized by their feed decimals.
+	num := big.NewInt(0).Exp(big.NewInt(10), big.NewInt(int64(tokenDecimals)+int64(tokenPrice.decimals)), nil)
+	den := big.NewInt(0).Set(tokenPrice.answer)
+	if c.nativeFeed != (common.Address{}) {
+		nativePrice, err := c.latestRoundData(c.nativeFeed)
+		if err != nil {
+			return nil, err
+		}
+		num.Mul(num, nativePrice.answer)
+		den.Mul(den, big.NewInt(0).Exp(big.NewInt(10), big.NewInt(int64(nativePrice.decimals)), nil))
+	}
+
+	rate := num.Div(num, den)
+	if rate.Sign() == 0 {
+		return nil, errors.New("oracle: exchange rate rounds to zero")
+	}
+	return rate, nil
+}
+
+func getTokenDecimals(eth *ethclient.Client, token common.Address) (uint8, error) {
+	var out []any
+	if err := bind.NewBoundContract(token, erc20, eth, eth, eth).Call(nil, &out, "decimals"); err != nil {
+		return 0, err
+	}
+	return out[0].(uint8), nil
+}
@@ -0,0 +1,34 @@
+package oracle
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var bpsDenominator = big.NewInt(10000)
+
+type markup struct {
+	oracle Oracle
+	bps    *big.Int
+}
+
+// WithMarkup returns an Oracle that increases every exchange rate from the given Oracle by a number of
+// basis points. This allows the paymaster to charge a premium over the market rate.
+func WithMarkup(o Oracle, bps int64) Oracle {
+	return &markup{
+		oracle: o,
+		bps:    big.NewInt(bps),
+	}
+}
+
+// GetExchangeRate implements the Oracle interface.
+func (m *markup) GetExchangeRate(token common.Address) (*big.Int, error) {
+	rate, err := m.oracle.GetExchangeRate(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rate = big.NewInt(0).Mul(rate, big.NewInt(0).Add(bpsDenominator, m.bps))
+	return rate.Div(rate, bpsDenominator), nil
+}
@@ -0,0 +1,45 @@
+package oracle
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrTokenNotSupported is returned when an Oracle has no price source for the requested token.
+	ErrTokenNotSupported = errors.New("oracle: token not supported")
+
+	// ErrStaleAnswer is returned when the latest answer from a price source is older than the allowed
+	// staleness threshold.
+	ErrStaleAnswer = errors.New("oracle: stale answer")
+)
+
+// Oracle returns the exchange rate for an ERC-20 token. The rate is denominated in the token's smallest
+// unit per 1 ether (i.e. 1e18 wei) of gas cost. This is the exchangeRate value expected by the
+// VerifyingPaymaster.
+type Oracle interface {
+	GetExchangeRate(token common.Address) (*big.Int, error)
+}
+
+// Router is an Oracle that dispatches to a different Oracle for each token.
+type Router map[common.Address]Oracle
+
+// GetExchangeRate implements the Oracle interface.
+func (r Router) GetExchangeRate(token common.Address) (*big.Int, error) {
+	o, ok := r[token]
+	if !ok {
+		return nil, ErrTokenNotSupported
+	}
+	return o.GetExchangeRate(token)
+}
+
+// Tokens returns all tokens that have a price source.
+func (r Router) Tokens() []common.Address {
+	tokens := []common.Address{}
+	for token := range r {
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
@@ -0,0 +1,77 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	ChainlinkSource = "chainlink"
+	UniswapV3Source = "uniswapv3"
+)
+
+type Opts struct {
+	MarkupBps    int64
+	CacheTTL     time.Duration
+	MaxStaleness time.Duration
+	TWAPWindow   time.Duration
+}
+
+// NewFromSource returns an on-chain Oracle from a source string in the format "<type>:<address>[:<address>]".
+// Supported formats are:
+//   - chainlink:<token feed>
+//   - chainlink:<token feed>:<native feed>
+//   - uniswapv3:<pool>
+func NewFromSource(eth *ethclient.Client, source string, opts *Opts) (Oracle, error) {
+	parts := strings.Split(strings.TrimSpace(source), ":")
+	for _, addr := range parts[1:] {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("oracle: bad address %s in source %s", addr, source)
+		}
+	}
+
+	switch {
+	case parts[0] == ChainlinkSource && len(parts) == 2:
+		return NewChainlink(eth, common.HexToAddress(parts[1]), common.Address{}, opts.MaxStaleness), nil
+	case parts[0] == ChainlinkSource && len(parts) == 3:
+		return NewChainlink(
+			eth,
+			common.HexToAddress(parts[1]),
+			common.HexToAddress(parts[2]),
+			opts.MaxStaleness,
+		), nil
+	case parts[0] == UniswapV3Source && len(parts) == 2:
+		return NewUniswapV3(eth, common.HexToAddress(parts[1]), opts.TWAPWindow), nil
+	default:
+		return nil, fmt.Errorf("oracle: source %s not recognized", source)
+	}
+}
+
+// New returns a Router for all tokens with either a static exchange rate or an on-chain source. On-chain
+// sources take precedence over static rates for the same token. Every rate is cached and has the markup
+// applied.
+func New(
+	eth *ethclient.Client,
+	static map[common.Address]*big.Int,
+	sources map[common.Address]string,
+	opts *Opts,
+) (Router, error) {
+	r := Router{}
+	for token, rate := range static {
+		r[token] = WithMarkup(Static{token: rate}, opts.MarkupBps)
+	}
+	for token, source := range sources {
+		o, err := NewFromSource(eth, source, opts)
+		if err != nil {
+			return nil, err
+		}
+		r[token] = WithMarkup(WithCache(o, opts.CacheTTL), opts.MarkupBps)
+	}
+
+	return r, nil
+}
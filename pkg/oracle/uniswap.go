@@ -0,0 +1,114 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	tickBase      = big.NewFloat(1.0001).SetPrec(256)
+	weiPerNative  = big.NewFloat(1e18).SetPrec(256)
+	floatPrec     = uint(256)
+	minTWAPWindow = time.Second
+)
+
+// UniswapV3 is an Oracle that derives prices from the time-weighted average tick of a Uniswap V3 pool.
+type UniswapV3 struct {
+	eth    *ethclient.Client
+	pool   common.Address
+	window time.Duration
+}
+
+// NewUniswapV3 returns an Oracle that reads the TWAP of a Uniswap V3 pool over the given window. The pool
+// must pair the token with the wrapped native currency of the chain (e.g. USDC / WETH).
+func NewUniswapV3(eth *ethclient.Client, pool common.Address, window time.Duration) *UniswapV3 {
+	if window < minTWAPWindow {
+		window = minTWAPWindow
+	}
+
+	return &UniswapV3{
+		eth:    eth,
+		pool:   pool,
+		window: window,
+	}
+}
+
+// pow returns 1.0001^tick using exponentiation by squaring.
+func pow(tick int64) *big.Float {
+	neg := tick < 0
+	if neg {
+		tick = -tick
+	}
+
+	result := big.NewFloat(1).SetPrec(floatPrec)
+	base := new(big.Float).SetPrec(floatPrec).Set(tickBase)
+	for tick > 0 {
+		if tick&1 == 1 {
+			result.Mul(result, base)
+		}
+		base.Mul(base, base)
+		tick >>= 1
+	}
+
+	if neg {
+		return new(big.Float).SetPrec(floatPrec).Quo(big.NewFloat(1).SetPrec(floatPrec), result)
+	}
+	return result
+}
+
+func (u *UniswapV3) getTWAPTick() (int64, error) {
+	pool := bind.NewBoundContract(u.pool, uniswapV3Pool, u.eth, u.eth, u.eth)
+
+	secs := uint32(u.window.Seconds())
+	var out []any
+	if err := pool.Call(nil, &out, "observe", []uint32{secs, 0}); err != nil {
+		return 0, err
+	}
+
+	cumulatives := out[0].([]*big.Int)
+	delta := big.NewInt(0).Sub(cumulatives[1], cumulatives[0])
+
+	// Euclidean division rounds towards negative infinity to match Uniswap's OracleLibrary.consult.
+	return delta.Div(delta, big.NewInt(int64(secs))).Int64(), nil
+}
+
+// GetExchangeRate implements the Oracle interface.
+func (u *UniswapV3) GetExchangeRate(token common.Address) (*big.Int, error) {
+	pool := bind.NewBoundContract(u.pool, uniswapV3Pool, u.eth, u.eth, u.eth)
+
+	var token0, token1 []any
+	if err := pool.Call(nil, &token0, "token0"); err != nil {
+		return nil, err
+	}
+	if err := pool.Call(nil, &token1, "token1"); err != nil {
+		return nil, err
+	}
+
+	tick, err := u.getTWAPTick()
+	if err != nil {
+		return nil, err
+	}
+
+	// 1.0001^tick is the price of token0 denominated in token1, both in their smallest unit.
+	price := pow(tick)
+	rate := new(big.Float).SetPrec(floatPrec)
+	switch token {
+	case token0[0].(common.Address):
+		rate.Quo(weiPerNative, price)
+	case token1[0].(common.Address):
+		rate.Mul(weiPerNative, price)
+	default:
+		return nil, fmt.Errorf("oracle: pool %s does not contain token %s", u.pool.Hex(), token.Hex())
+	}
+
+	out, _ := rate.Int(nil)
+	if out.Sign() == 0 {
+		return nil, fmt.Errorf("oracle: exchange rate for %s rounds to zero", token.Hex())
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package packedop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// erc165InterfaceID is the interface ID of supportsInterface(bytes4). It is also the function selector.
+var erc165InterfaceID = hexutil.MustDecode("0x01ffc9a7")
+
+// isRevert returns true if err is from a reverted eth_call rather than a failed request.
+func isRevert(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) && dataErr.ErrorData() != nil {
+		return true
+	}
+	return strings.Contains(err.Error(), "execution reverted")
+}
+
+// IsEntryPoint returns true if the contract at ep is an EntryPoint v0.7. Unlike v0.6, the v0.7 EntryPoint
+// implements ERC-165 so a call to supportsInterface will revert on any earlier version.
+func IsEntryPoint(eth *ethclient.Client, ep common.Address) (bool, error) {
+	data := append(common.CopyBytes(erc165InterfaceID), common.RightPadBytes(erc165InterfaceID, 32)...)
+	out, err := eth.CallContract(context.Background(), ethereum.CallMsg{To: &ep, Data: data}, nil)
+	if err != nil && isRevert(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if len(out) == 0 {
+		return false, fmt.Errorf("entryPoint: no contract at %s", ep.Hex())
+	}
+
+	return big.NewInt(0).SetBytes(out).Sign() != 0, nil
+}
@@ -0,0 +1,283 @@
+package packedop
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+)
+
+var (
+	address, _   = abi.NewType("address", "", nil)
+	uint256, _   = abi.NewType("uint256", "", nil)
+	bytes32, _   = abi.NewType("bytes32", "", nil)
+	bytesType, _ = abi.NewType("bytes", "", nil)
+
+	packedUserOpFields = []abi.ArgumentMarshaling{
+		{Name: "sender", Type: "address"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "initCode", Type: "bytes"},
+		{Name: "callData", Type: "bytes"},
+		{Name: "accountGasLimits", Type: "bytes32"},
+		{Name: "preVerificationGas", Type: "uint256"},
+		{Name: "gasFees", Type: "bytes32"},
+		{Name: "paymasterAndData", Type: "bytes"},
+		{Name: "signature", Type: "bytes"},
+	}
+	packedUserOpType, _  = abi.NewType("tuple", "", packedUserOpFields)
+	packedUserOpsType, _ = abi.NewType("tuple[]", "", packedUserOpFields)
+
+	// HandleOpsMethod is the handleOps function of EntryPoint v0.7.
+	HandleOpsMethod = abi.NewMethod(
+		"handleOps",
+		"handleOps",
+		abi.Function,
+		"",
+		false,
+		false,
+		abi.Arguments{
+			{Name: "ops", Type: packedUserOpsType},
+			{Name: "beneficiary", Type: address},
+		},
+		nil,
+	)
+
+	// ValidateUserOpMethod is the validateUserOp function of an account for EntryPoint v0.7.
+	ValidateUserOpMethod = abi.NewMethod(
+		"validateUserOp",
+		"validateUserOp",
+		abi.Function,
+		"",
+		false,
+		false,
+		abi.Arguments{
+			{Name: "userOp", Type: packedUserOpType},
+			{Name: "userOpHash", Type: bytes32},
+			{Name: "missingAccountFunds", Type: uint256},
+		},
+		abi.Arguments{{Name: "validationData", Type: uint256}},
+	)
+
+	// ExecuteUserOpMethod is the optional executeUserOp function of an account for EntryPoint v0.7. If the
+	// callData of an op starts with its selector, the EntryPoint calls it with the whole op instead.
+	ExecuteUserOpMethod = abi.NewMethod(
+		"executeUserOp",
+		"executeUserOp",
+		abi.Function,
+		"",
+		false,
+		false,
+		abi.Arguments{
+			{Name: "userOp", Type: packedUserOpType},
+			{Name: "userOpHash", Type: bytes32},
+		},
+		nil,
+	)
+
+	// CreateSenderMethod is the createSender function of the SenderCreator for EntryPoint v0.7.
+	CreateSenderMethod = abi.NewMethod(
+		"createSender",
+		"createSender",
+		abi.Function,
+		"",
+		false,
+		false,
+		abi.Arguments{{Name: "initCode", Type: bytesType}},
+		abi.Arguments{{Name: "sender", Type: address}},
+	)
+)
+
+// UserOperation represents an ERC-4337 v0.7 UserOperation in its unpacked RPC format.
+type UserOperation struct {
+	Sender                        common.Address `json:"sender"                        mapstructure:"sender"                        validate:"required"`
+	Nonce                         *big.Int       `json:"nonce"                         mapstructure:"nonce"                         validate:"required"`
+	Factory                       common.Address `json:"factory"                       mapstructure:"factory"`
+	FactoryData                   []byte         `json:"factoryData"                   mapstructure:"factoryData"`
+	CallData                      []byte         `json:"callData"                      mapstructure:"callData"                      validate:"required"`
+	CallGasLimit                  *big.Int       `json:"callGasLimit"                  mapstructure:"callGasLimit"                  validate:"required"`
+	VerificationGasLimit          *big.Int       `json:"verificationGasLimit"          mapstructure:"verificationGasLimit"          validate:"required"`
+	PreVerificationGas            *big.Int       `json:"preVerificationGas"            mapstructure:"preVerificationGas"            validate:"required"`
+	MaxFeePerGas                  *big.Int       `json:"maxFeePerGas"                  mapstructure:"maxFeePerGas"                  validate:"required"`
+	MaxPriorityFeePerGas          *big.Int       `json:"maxPriorityFeePerGas"          mapstructure:"maxPriorityFeePerGas"          validate:"required"`
+	Paymaster                     common.Address `json:"paymaster"                     mapstructure:"paymaster"`
+	PaymasterVerificationGasLimit *big.Int       `json:"paymasterVerificationGasLimit" mapstructure:"paymasterVerificationGasLimit"`
+	PaymasterPostOpGasLimit       *big.Int       `json:"paymasterPostOpGasLimit"       mapstructure:"paymasterPostOpGasLimit"`
+	PaymasterData                 []byte         `json:"paymasterData"                 mapstructure:"paymasterData"`
+	Signature                     []byte         `json:"signature"                     mapstructure:"signature"                     validate:"required"`
+}
+
+// packUints returns two uint128 values packed into a single bytes32.
+func packUints(high *big.Int, low *big.Int) [32]byte {
+	var out [32]byte
+	copy(out[:16], common.LeftPadBytes(high.Bytes(), 16))
+	copy(out[16:], common.LeftPadBytes(low.Bytes(), 16))
+	return out
+}
+
+// GetInitCode returns the factory and factoryData concatenated into a single initCode.
+func (op *UserOperation) GetInitCode() []byte {
+	if op.Factory == (common.Address{}) {
+		return []byte{}
+	}
+	return append(op.Factory.Bytes(), op.FactoryData...)
+}
+
+// GetPaymasterAndData returns the paymaster fields concatenated into a single paymasterAndData.
+func (op *UserOperation) GetPaymasterAndData() []byte {
+	if op.Paymaster == (common.Address{}) {
+		return []byte{}
+	}
+
+	pnd := op.Paymaster.Bytes()
+	pnd = append(pnd, common.LeftPadBytes(op.PaymasterVerificationGasLimit.Bytes(), 16)...)
+	pnd = append(pnd, common.LeftPadBytes(op.PaymasterPostOpGasLimit.Bytes(), 16)...)
+	return append(pnd, op.PaymasterData...)
+}
+
+// GetAccountGasLimits returns the packed verificationGasLimit and callGasLimit.
+func (op *UserOperation) GetAccountGasLimits() [32]byte {
+	return packUints(op.VerificationGasLimit, op.CallGasLimit)
+}
+
+// GetGasFees returns the packed maxPriorityFeePerGas and maxFeePerGas.
+func (op *UserOperation) GetGasFees() [32]byte {
+	return packUints(op.MaxPriorityFeePerGas, op.MaxFeePerGas)
+}
+
+// GetMaxGasAvailable returns the max amount of gas that can be consumed by this UserOperation.
+func (op *UserOperation) GetMaxGasAvailable() *big.Int {
+	total := big.NewInt(0).Add(op.VerificationGasLimit, op.CallGasLimit)
+	total.Add(total, op.PreVerificationGas)
+	total.Add(total, op.PaymasterVerificationGasLimit)
+	return total.Add(total, op.PaymasterPostOpGasLimit)
+}
+
+// GetMaxPrefund returns the max amount of wei required to pay for gas fees by either the sender or
+// paymaster.
+func (op *UserOperation) GetMaxPrefund() *big.Int {
+	return big.NewInt(0).Mul(op.GetMaxGasAvailable(), op.MaxFeePerGas)
+}
+
+// Pack returns the PackedUserOperation struct that is passed to contracts on chain.
+func (op *UserOperation) Pack() contract.PackedUserOperation {
+	return contract.PackedUserOperation{
+		Sender:             op.Sender,
+		Nonce:              op.Nonce,
+		InitCode:           op.GetInitCode(),
+		CallData:           op.CallData,
+		AccountGasLimits:   op.GetAccountGasLimits(),
+		PreVerificationGas: op.PreVerificationGas,
+		GasFees:            op.GetGasFees(),
+		PaymasterAndData:   op.GetPaymasterAndData(),
+		Signature:          op.Signature,
+	}
+}
+
+// Encode returns the ABI encoding of the packed op as it appears in the calldata of handleOps. This is the
+// data that a bundler pays calldata costs for.
+func (op *UserOperation) Encode() []byte {
+	args := abi.Arguments{{Name: "userOp", Type: packedUserOpType}}
+	packed, _ := args.Pack(op.Pack())
+
+	// Remove the offset of the dynamic tuple.
+	return packed[32:]
+}
+
+// PackForSignature returns a minimal message of the userOp. This can be used to generate a userOpHash.
+func (op *UserOperation) PackForSignature() []byte {
+	args := abi.Arguments{
+		{Name: "sender", Type: address},
+		{Name: "nonce", Type: uint256},
+		{Name: "hashInitCode", Type: bytes32},
+		{Name: "hashCallData", Type: bytes32},
+		{Name: "accountGasLimits", Type: bytes32},
+		{Name: "preVerificationGas", Type: uint256},
+		{Name: "gasFees", Type: bytes32},
+		{Name: "hashPaymasterAndData", Type: bytes32},
+	}
+	packed, _ := args.Pack(
+		op.Sender,
+		op.Nonce,
+		crypto.Keccak256Hash(op.GetInitCode()),
+		crypto.Keccak256Hash(op.CallData),
+		op.GetAccountGasLimits(),
+		op.PreVerificationGas,
+		op.GetGasFees(),
+		crypto.Keccak256Hash(op.GetPaymasterAndData()),
+	)
+
+	return packed
+}
+
+// GetUserOpHash returns the hash of the userOp + entryPoint address + chainID.
+func (op *UserOperation) GetUserOpHash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	return crypto.Keccak256Hash(
+		crypto.Keccak256(op.PackForSignature()),
+		common.LeftPadBytes(entryPoint.Bytes(), 32),
+		common.LeftPadBytes(chainID.Bytes(), 32),
+	)
+}
+
+// ToUserOperation returns a v0.6 representation of the op with the same initCode, callData, and encoded
+// size. This allows v0.6 utilities such as preVerificationGas calculations and policies to be reused.
+func (op *UserOperation) ToUserOperation() *userop.UserOperation {
+	return &userop.UserOperation{
+		Sender:               op.Sender,
+		Nonce:                op.Nonce,
+		InitCode:             op.GetInitCode(),
+		CallData:             op.CallData,
+		CallGasLimit:         op.CallGasLimit,
+		VerificationGasLimit: op.VerificationGasLimit,
+		PreVerificationGas:   op.PreVerificationGas,
+		MaxFeePerGas:         op.MaxFeePerGas,
+		MaxPriorityFeePerGas: op.MaxPriorityFeePerGas,
+		PaymasterAndData:     op.GetPaymasterAndData(),
+		Signature:            op.Signature,
+	}
+}
+
+// MarshalJSON returns a JSON encoding of the UserOperation.
+func (op *UserOperation) MarshalJSON() ([]byte, error) {
+	out := map[string]any{
+		"sender":               op.Sender.String(),
+		"nonce":                hexutil.EncodeBig(op.Nonce),
+		"callData":             hexutil.Encode(op.CallData),
+		"callGasLimit":         hexutil.EncodeBig(op.CallGasLimit),
+		"verificationGasLimit": hexutil.EncodeBig(op.VerificationGasLimit),
+		"preVerificationGas":   hexutil.EncodeBig(op.PreVerificationGas),
+		"maxFeePerGas":         hexutil.EncodeBig(op.MaxFeePerGas),
+		"maxPriorityFeePerGas": hexutil.EncodeBig(op.MaxPriorityFeePerGas),
+		"signature":            hexutil.Encode(op.Signature),
+	}
+	if op.Factory != (common.Address{}) {
+		out["factory"] = op.Factory.String()
+		out["factoryData"] = hexutil.Encode(op.FactoryData)
+	}
+	if op.Paymaster != (common.Address{}) {
+		out["paymaster"] = op.Paymaster.String()
+		out["paymasterVerificationGasLimit"] = hexutil.EncodeBig(op.PaymasterVerificationGasLimit)
+		out["paymasterPostOpGasLimit"] = hexutil.EncodeBig(op.PaymasterPostOpGasLimit)
+		out["paymasterData"] = hexutil.Encode(op.PaymasterData)
+	}
+
+	return json.Marshal(out)
+}
+
+// ToMap returns the current UserOp struct as a map type.
+func (op *UserOperation) ToMap() (map[string]any, error) {
+	data, err := op.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var opData map[string]any
+	if err := json.Unmarshal(data, &opData); err != nil {
+		return nil, err
+	}
+	return opData, nil
+}
@@ -0,0 +1,119 @@
+package packedop
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+)
+
+var (
+	validate = validator.New()
+	onlyOnce = sync.Once{}
+
+	ErrBadUserOperationData = errors.New("cannot decode UserOperation")
+)
+
+func exactFieldMatch(mapKey, fieldName string) bool {
+	return mapKey == fieldName
+}
+
+func decodeOpTypes(
+	f reflect.Kind,
+	t reflect.Kind,
+	data interface{}) (interface{}, error) {
+	// String to common.Address conversion
+	if f == reflect.String && t == reflect.Array {
+		return common.HexToAddress(data.(string)), nil
+	}
+
+	// String to big.Int conversion
+	if f == reflect.String && t == reflect.Struct {
+		n := new(big.Int)
+		n, ok := n.SetString(data.(string), 0)
+		if !ok {
+			return nil, errors.New("bigInt conversion failed")
+		}
+		return n, nil
+	}
+
+	// Float64 to big.Int conversion
+	if f == reflect.Float64 && t == reflect.Struct {
+		n, ok := data.(float64)
+		if !ok {
+			return nil, errors.New("bigInt conversion failed")
+		}
+		return big.NewInt(int64(n)), nil
+	}
+
+	// String to []byte conversion
+	if f == reflect.String && t == reflect.Slice {
+		byteStr := data.(string)
+		if len(byteStr) < 2 || byteStr[:2] != "0x" {
+			return nil, errors.New("not byte string")
+		}
+
+		b, err := hex.DecodeString(byteStr[2:])
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	return data, nil
+}
+
+func validateBigIntType(field reflect.Value) interface{} {
+	value, ok := field.Interface().(big.Int)
+	if !ok || value.Cmp(big.NewInt(0)) == -1 {
+		return nil
+	}
+
+	return field
+}
+
+func orZero(n *big.Int) *big.Int {
+	if n == nil {
+		return big.NewInt(0)
+	}
+	return n
+}
+
+// New decodes a map into a UserOperation object and validates all the fields are correctly typed. Factory
+// and paymaster fields are optional.
+func New(data map[string]any) (*UserOperation, error) {
+	var op UserOperation
+
+	// Convert map to struct
+	config := &mapstructure.DecoderConfig{
+		DecodeHook: decodeOpTypes,
+		Result:     &op,
+		MatchName:  exactFieldMatch,
+	}
+	decoder, err := mapstructure.NewDecoder(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(data); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadUserOperationData, err)
+	}
+
+	// Validate struct
+	onlyOnce.Do(func() {
+		validate.RegisterCustomTypeFunc(validateBigIntType, big.Int{})
+	})
+	err = validate.Struct(op)
+	if err != nil {
+		return nil, err
+	}
+
+	op.PaymasterVerificationGasLimit = orZero(op.PaymasterVerificationGasLimit)
+	op.PaymasterPostOpGasLimit = orZero(op.PaymasterPostOpGasLimit)
+	return &op, nil
+}
@@ -0,0 +1,167 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/addrset"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/calldata"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/estimator"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+var (
+	// ErrRejected is wrapped by all errors returned when a UserOperation does not satisfy a policy.
+	ErrRejected = errors.New("policy: rejected")
+)
+
+func containsAddress(list []common.Address, addr common.Address) bool {
+	for _, a := range list {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectorRule allows or denies a set of 4 byte function selectors. It follows the same semantics as
+// addrset.Set.
+type SelectorRule struct {
+	Allow []hexutil.Bytes `json:"allow" yaml:"allow"`
+	Deny  []hexutil.Bytes `json:"deny"  yaml:"deny"`
+}
+
+func containsSelector(list []hexutil.Bytes, sel [4]byte) bool {
+	for _, s := range list {
+		if len(s) == 4 && [4]byte(s) == sel {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *SelectorRule) isAllowed(sel [4]byte) bool {
+	if r == nil {
+		return true
+	}
+	if containsSelector(r.Deny, sel) {
+		return false
+	}
+	return len(r.Allow) == 0 || containsSelector(r.Allow, sel)
+}
+
+// Policy is a set of rules that a UserOperation must satisfy in order to be sponsored. Any rule that is
+// not set will allow all values.
+type Policy struct {
+	ID         string        `json:"id"         yaml:"id"`
+	Senders    *addrset.Set  `json:"senders"    yaml:"senders"`
+	Factories  *addrset.Set  `json:"factories"  yaml:"factories"`
+	Targets    *addrset.Set  `json:"targets"    yaml:"targets"`
+	Selectors  *SelectorRule `json:"selectors"  yaml:"selectors"`
+	MaxGasCost *big.Int      `json:"maxGasCost" yaml:"maxGasCost"`
+
+	// Paymasters restricts sponsorship to a subset of the paymasters configured for an EntryPoint.
+	Paymasters []common.Address `json:"paymasters" yaml:"paymasters"`
+
+	// Webhook is an external service that must approve each UserOperation before it is signed.
+	Webhook *Webhook `json:"webhook" yaml:"webhook"`
+
+	// ValidFor and MaxValidFor override the server defaults for the validity window of a signature.
+	ValidFor    time.Duration `json:"validFor"    yaml:"validFor"`
+	MaxValidFor time.Duration `json:"maxValidFor" yaml:"maxValidFor"`
+
+	// Gas overrides the server defaults for gas buffers and bounds during estimation.
+	Gas *estimator.Limits `json:"gas" yaml:"gas"`
+}
+
+// GasLimits returns the gas overrides of the policy or nil if there are none.
+func (p *Policy) GasLimits() *estimator.Limits {
+	if p == nil {
+		return nil
+	}
+	return p.Gas
+}
+
+// FilterPaymasters returns the paymasters that are allowed by the policy. A nil Policy or one without
+// paymasters set will allow all of them.
+func (p *Policy) FilterPaymasters(pms []common.Address) []common.Address {
+	if p == nil || len(p.Paymasters) == 0 {
+		return pms
+	}
+
+	out := []common.Address{}
+	for _, pm := range pms {
+		if containsAddress(p.Paymasters, pm) {
+			out = append(out, pm)
+		}
+	}
+	return out
+}
+
+// RejectedData is the data field of the JSON-RPC error returned when an op is rejected by a policy.
+type RejectedData struct {
+	PolicyID string `json:"policyId"`
+	Reason   string `json:"reason"`
+}
+
+// Reject returns an error wrapping ErrRejected for the given policy ID and reason.
+func Reject(id string, format string, a ...any) error {
+	reason := fmt.Sprintf(format, a...)
+	return rpcerrors.New(
+		rpcerrors.CodePolicyRejected,
+		fmt.Errorf("%w: %s: %s", ErrRejected, id, reason),
+		&RejectedData{PolicyID: id, Reason: reason},
+	)
+}
+
+func (p *Policy) reject(format string, a ...any) error {
+	return Reject(p.ID, format, a...)
+}
+
+// Evaluate checks the UserOperation against all rules in the policy and returns an error wrapping
+// ErrRejected if any rule is not satisfied. The maxCost is the max amount of wei the paymaster could be
+// charged for the op with its final gas limits. A nil Policy will allow all UserOperations.
+func (p *Policy) Evaluate(op *userop.UserOperation, maxCost *big.Int) error {
+	if p == nil {
+		return nil
+	}
+
+	if !p.Senders.IsAllowed(op.Sender) {
+		return p.reject("sender %s not allowed", op.Sender.Hex())
+	}
+
+	if len(op.InitCode) > 0 && !p.Factories.IsAllowed(op.GetFactory()) {
+		return p.reject("factory %s not allowed", op.GetFactory().Hex())
+	}
+
+	if p.Targets != nil || p.Selectors != nil {
+		calls, err := calldata.Decode(op.CallData)
+		if err != nil {
+			return p.reject("cannot decode callData: %s", err)
+		}
+
+		for _, call := range calls {
+			if !p.Targets.IsAllowed(call.Target) {
+				return p.reject("target %s not allowed", call.Target.Hex())
+			}
+
+			sel := call.Selector()
+			if !p.Selectors.isAllowed(sel) {
+				return p.reject("selector %s not allowed", hexutil.Encode(sel[:]))
+			}
+		}
+	}
+
+	if p.MaxGasCost != nil {
+		if maxCost.Cmp(p.MaxGasCost) > 0 {
+			return p.reject("max gas cost %s exceeds limit %s", maxCost, p.MaxGasCost)
+		}
+	}
+
+	return nil
+}
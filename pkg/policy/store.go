@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/stackup-wallet/stackup-paymaster/internal/yamlutil"
+)
+
+// DefaultPolicyID is the policy used for requests that do not specify a policyId in the context.
+const DefaultPolicyID = "default"
+
+type file struct {
+	Policies []*Policy `json:"policies" yaml:"policies"`
+}
+
+// Store holds all policies by ID.
+type Store struct {
+	policies map[string]*Policy
+}
+
+// Load reads all policies from a YAML or JSON file.
+func Load(path string) (*Store, error) {
+	var f file
+	if err := yamlutil.ReadFile(path, &f); err != nil {
+		return nil, fmt.Errorf("policy: %s", err)
+	}
+
+	s := &Store{policies: map[string]*Policy{}}
+	for _, p := range f.Policies {
+		if p.ID == "" {
+			return nil, fmt.Errorf("policy: missing id in %s", path)
+		}
+		if p.Webhook != nil && p.Webhook.URL == "" {
+			return nil, fmt.Errorf("policy: %s: missing webhook url in %s", p.ID, path)
+		}
+		if p.Webhook != nil && p.Webhook.Fallback != "" &&
+			p.Webhook.Fallback != FallbackApprove && p.Webhook.Fallback != FallbackDeny {
+			return nil, fmt.Errorf("policy: %s: webhook fallback %s not recognized", p.ID, p.Webhook.Fallback)
+		}
+		if err := p.Gas.Validate(); err != nil {
+			return nil, fmt.Errorf("policy: %s: %s in %s", p.ID, err, path)
+		}
+		if _, ok := s.policies[p.ID]; ok {
+			return nil, fmt.Errorf("policy: duplicate id %s in %s", p.ID, path)
+		}
+		s.policies[p.ID] = p
+	}
+
+	return s, nil
+}
+
+// Get returns the policy for the given ID. If ID is empty it will return the default policy. A nil Store
+// will return a nil Policy which allows all UserOperations.
+func (s *Store) Get(id string) (*Policy, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	if id == "" {
+		id = DefaultPolicyID
+	}
+	p, ok := s.policies[id]
+	if !ok {
+		return nil, Reject(id, "policyId not found")
+	}
+	return p, nil
+}
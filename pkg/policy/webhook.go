@@ -0,0 +1,140 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-logr/logr"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/calldata"
+)
+
+const (
+	// WebhookSignatureHeader is the request header with the hex encoded HMAC-SHA256 of the timestamp and
+	// body, joined by a ".", using the webhook secret as key.
+	WebhookSignatureHeader = "X-Paymaster-Signature"
+
+	// WebhookTimestampHeader is the request header with the unix time in seconds that the request was sent.
+	WebhookTimestampHeader = "X-Paymaster-Timestamp"
+
+	// FallbackApprove will sponsor the op if the webhook can't be reached or gives an invalid response.
+	FallbackApprove = "approve"
+
+	// FallbackDeny will reject the op if the webhook can't be reached or gives an invalid response.
+	FallbackDeny = "deny"
+
+	defaultWebhookTimeout = 2 * time.Second
+)
+
+var webhookClient = &http.Client{}
+
+// Webhook sends each UserOperation to an external service that decides whether or not it can be sponsored.
+type Webhook struct {
+	URL     string        `json:"url"     yaml:"url"`
+	Secret  string        `json:"secret"  yaml:"secret"`
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// Fallback is the decision to use if the webhook fails. Defaults to FallbackDeny.
+	Fallback string `json:"fallback" yaml:"fallback"`
+}
+
+// WebhookRequest is the body POSTed to a webhook.
+type WebhookRequest struct {
+	PolicyID      string           `json:"policyId"`
+	ChainID       string           `json:"chainId"`
+	EntryPoint    common.Address   `json:"entryPoint"`
+	Sender        common.Address   `json:"sender"`
+	UserOperation any              `json:"userOperation"`
+	Calls         []*calldata.Call `json:"calls,omitempty"`
+	Context       map[string]any   `json:"context"`
+}
+
+// WebhookResponse is the expected body of a successful response from a webhook.
+type WebhookResponse struct {
+	Approve bool   `json:"approve"`
+	Reason  string `json:"reason"`
+}
+
+func (w *Webhook) sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *Webhook) call(req *WebhookRequest) (*WebhookResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(WebhookTimestampHeader, timestamp)
+	httpReq.Header.Set(WebhookSignatureHeader, w.sign(timestamp, body))
+
+	httpRes, err := webhookClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("webhook: status %d", httpRes.StatusCode)
+	}
+	b, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return nil, err
+	}
+	var res WebhookResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("webhook: %s", err)
+	}
+	return &res, nil
+}
+
+// CallWebhook asks the webhook of the policy to approve the request and returns an error wrapping
+// ErrRejected if it is denied. If the webhook fails, the Fallback decision is used. A nil Policy or one
+// without a webhook will allow all requests.
+func (p *Policy) CallWebhook(req *WebhookRequest, l logr.Logger) error {
+	if p == nil || p.Webhook == nil {
+		return nil
+	}
+
+	req.PolicyID = p.ID
+	res, err := p.Webhook.call(req)
+	if err != nil {
+		// The error is only logged since it can contain details of the webhook that should not be exposed.
+		if p.Webhook.Fallback == FallbackApprove {
+			l.Error(err, "webhook error, using fallback", "fallback", FallbackApprove)
+			return nil
+		}
+		l.Error(err, "webhook error, using fallback", "fallback", FallbackDeny)
+		return p.reject("webhook unavailable")
+	}
+	if !res.Approve {
+		return p.reject("denied by webhook: %s", res.Reason)
+	}
+	return nil
+}
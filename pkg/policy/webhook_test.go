@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-logr/logr"
+)
+
+const testSecret = "secret"
+
+func newWebhookRequest() *WebhookRequest {
+	return &WebhookRequest{
+		ChainID:    "1",
+		EntryPoint: common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+		Sender:     common.HexToAddress("0x1306b01bC3e4AD202612D3843387e94737673F53"),
+		Context:    map[string]any{"type": "payg"},
+	}
+}
+
+func TestCallWebhookSignature(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		mac := hmac.New(sha256.New, []byte(testSecret))
+		mac.Write([]byte(r.Header.Get(WebhookTimestampHeader) + "." + string(body)))
+		if got, want := r.Header.Get(WebhookSignatureHeader), hex.EncodeToString(mac.Sum(nil)); got != want {
+			t.Errorf("got signature %s, want %s", got, want)
+		}
+
+		var req WebhookRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Error(err)
+			return
+		}
+		if req.PolicyID != "p1" {
+			t.Errorf("got policyId %s, want p1", req.PolicyID)
+		}
+		_, _ = w.Write([]byte(`{"approve":true}`))
+	}))
+	defer srv.Close()
+
+	p := &Policy{ID: "p1", Webhook: &Webhook{URL: srv.URL, Secret: testSecret}}
+	if err := p.CallWebhook(newWebhookRequest(), logr.Discard()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallWebhookDenied(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"approve":false,"reason":"sender blocked"}`))
+	}))
+	defer srv.Close()
+
+	// A denial from the webhook is not a failure so the fallback is not used.
+	p := &Policy{ID: "p1", Webhook: &Webhook{URL: srv.URL, Secret: testSecret, Fallback: FallbackApprove}}
+	err := p.CallWebhook(newWebhookRequest(), logr.Discard())
+	if !errors.Is(err, ErrRejected) {
+		t.Fatalf("got %v, want %v", err, ErrRejected)
+	}
+	if !strings.Contains(err.Error(), "sender blocked") {
+		t.Errorf("got %v, want reason sender blocked", err)
+	}
+}
+
+func TestCallWebhookFallback(t *testing.T) {
+	handlers := map[string]http.HandlerFunc{
+		"non-200": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"approve":true}`))
+		},
+		"invalid body": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`approve`))
+		},
+		"timeout": func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(500 * time.Millisecond):
+			}
+			_, _ = w.Write([]byte(`{"approve":true}`))
+		},
+	}
+
+	for name, h := range handlers {
+		srv := httptest.NewServer(h)
+		defer srv.Close()
+
+		for _, fallback := range []string{FallbackApprove, FallbackDeny, ""} {
+			p := &Policy{
+				ID: "p1",
+				Webhook: &Webhook{
+					URL:      srv.URL,
+					Secret:   testSecret,
+					Timeout:  50 * time.Millisecond,
+					Fallback: fallback,
+				},
+			}
+			err := p.CallWebhook(newWebhookRequest(), logr.Discard())
+			if fallback == FallbackApprove && err != nil {
+				t.Errorf("%s, fallback %q: got %v, want nil", name, fallback, err)
+			}
+			if fallback != FallbackApprove && !errors.Is(err, ErrRejected) {
+				t.Errorf("%s, fallback %q: got %v, want %v", name, fallback, err, ErrRejected)
+			}
+		}
+	}
+}
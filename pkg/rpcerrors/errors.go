@@ -0,0 +1,128 @@
+// Package rpcerrors defines the JSON-RPC errors returned by the paymaster. Each error has a stable code and
+// a structured data field so that clients can handle a failure without parsing its message.
+package rpcerrors
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	bundlerErrors "github.com/stackup-wallet/stackup-bundler/pkg/errors"
+)
+
+const (
+	// CodeInvalidParams is returned for a malformed userOp or context, or an unsupported EntryPoint, chain,
+	// or paymaster.
+	CodeInvalidParams = -32602
+
+	// CodeInternal is returned for any unexpected failure within the paymaster.
+	CodeInternal = -32603
+
+	// CodeSimulationFailed is returned when the op is rejected by the EntryPoint or account during gas
+	// estimation. This and CodeExecutionReverted are the same codes used by bundlers.
+	CodeSimulationFailed = -32500
+
+	// CodeExecutionReverted is returned when the callData of the op reverts during gas estimation.
+	CodeExecutionReverted = -32521
+
+	// CodePaymasterRejected is returned when the signed op is rejected by validatePaymasterUserOp on chain.
+	// This is the same code used by bundlers.
+	CodePaymasterRejected = -32501
+
+	// CodeBudgetExceeded is returned when a sponsorship would exceed a budget.
+	CodeBudgetExceeded = -32001
+
+	// CodePolicyRejected is returned when the op is not allowed by a sponsorship policy.
+	CodePolicyRejected = -32002
+
+	// CodeInsufficientDeposit is returned when the EntryPoint deposit of the paymaster is too low.
+	CodeInsufficientDeposit = -32003
+
+	// CodeNoVerifierKey is returned when there is no key for the on-chain verifier of the paymaster.
+	CodeNoVerifierKey = -32004
+
+	// CodeTokenNotSupported is returned when an ERC-20 token has no exchange rate.
+	CodeTokenNotSupported = -32005
+
+	// CodeOracleUnavailable is returned when the exchange rate of an ERC-20 token can't be trusted.
+	CodeOracleUnavailable = -32006
+
+	// CodeUnavailable is returned when a request to the node has failed.
+	CodeUnavailable = -32007
+
+	// CodeBlocked is returned when the sender, factory, or a call target of the op is on an access list.
+	CodeBlocked = -32008
+
+	// CodeBudgetBusy is returned when a budget can't be updated due to too many concurrent requests.
+	CodeBudgetBusy = -32009
+)
+
+// Error is an error with a JSON-RPC code and data. It wraps an underlying error so that errors.Is and
+// errors.As will continue to work on the original value.
+type Error struct {
+	code int
+	data any
+	err  error
+}
+
+// New returns an Error for err with the given code and data.
+func New(code int, err error, data any) error {
+	return &Error{code: code, data: data, err: err}
+}
+
+// InvalidParams returns an Error with CodeInvalidParams.
+func InvalidParams(err error) error {
+	return New(CodeInvalidParams, err, nil)
+}
+
+// Error returns the message field of the JSON-RPC error object.
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Code returns the code field of the JSON-RPC error object.
+func (e *Error) Code() int {
+	return e.code
+}
+
+// Data returns the data field of the JSON-RPC error object.
+func (e *Error) Data() any {
+	return e.data
+}
+
+// isUnavailable returns true if err was caused by a failed request to the node.
+func isUnavailable(err error) bool {
+	var netErr net.Error
+	var httpErr rpc.HTTPError
+	return errors.As(err, &netErr) ||
+		errors.As(err, &httpErr) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// Encode returns err as an RPCError that the JSON-RPC controller will send to the client as is. Errors from
+// the bundler keep their code and data. Any other error that is not typed is returned as CodeUnavailable if
+// it was caused by a failed request to the node or CodeInternal otherwise.
+func Encode(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	var rpcErr *bundlerErrors.RPCError
+	switch {
+	case errors.As(err, &e):
+		return bundlerErrors.NewRPCError(e.code, err.Error(), e.data)
+	case errors.As(err, &rpcErr):
+		return bundlerErrors.NewRPCError(rpcErr.Code(), err.Error(), rpcErr.Data())
+	case isUnavailable(err):
+		return bundlerErrors.NewRPCError(CodeUnavailable, err.Error(), nil)
+	default:
+		return bundlerErrors.NewRPCError(CodeInternal, err.Error(), nil)
+	}
+}
@@ -0,0 +1,31 @@
+package rpcerrors
+
+import (
+	"errors"
+
+	bundlerErrors "github.com/stackup-wallet/stackup-bundler/pkg/errors"
+)
+
+// SimulationData is the data field of the JSON-RPC error returned when an op fails during gas estimation.
+type SimulationData struct {
+	// Reason is the revert reason from the EntryPoint, account, or callData.
+	Reason string `json:"reason"`
+
+	// Revert is any additional data from the simulation, such as the decoded FailedOp error.
+	Revert any `json:"revert,omitempty"`
+}
+
+// Simulation returns an Error with the revert reason if err is a revert from gas estimation. Any other error
+// is returned as is.
+func Simulation(err error) error {
+	var rpcErr *bundlerErrors.RPCError
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+
+	code := CodeSimulationFailed
+	if rpcErr.Code() == CodeExecutionReverted {
+		code = CodeExecutionReverted
+	}
+	return New(code, err, &SimulationData{Reason: rpcErr.Error(), Revert: rpcErr.Data()})
+}
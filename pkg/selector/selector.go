@@ -0,0 +1,82 @@
+// Package selector picks which paymaster to sponsor an op with when several are configured for an
+// EntryPoint.
+package selector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/deposit"
+)
+
+// Strategy is the method used to select a paymaster.
+type Strategy string
+
+const (
+	// First always selects the first paymaster.
+	First Strategy = "first"
+
+	// RoundRobin rotates through all paymasters of an EntryPoint.
+	RoundRobin Strategy = "round_robin"
+
+	// MaxDeposit selects the paymaster with the largest deposit on the EntryPoint.
+	MaxDeposit Strategy = "max_deposit"
+)
+
+// ParseStrategy returns the Strategy for the given string.
+func ParseStrategy(s string) (Strategy, error) {
+	switch st := Strategy(s); st {
+	case First, RoundRobin, MaxDeposit:
+		return st, nil
+	default:
+		return "", fmt.Errorf("selector: unknown strategy %s", s)
+	}
+}
+
+// Selector selects a paymaster from a list of candidates based on a Strategy.
+type Selector struct {
+	strategy Strategy
+	deposits *deposit.Monitor
+
+	mu   sync.Mutex
+	next map[common.Address]uint64
+}
+
+// New returns a Selector for the given strategy. The deposit Monitor is required for MaxDeposit.
+func New(strategy Strategy, deposits *deposit.Monitor) (*Selector, error) {
+	if strategy == MaxDeposit && deposits == nil {
+		return nil, fmt.Errorf("selector: %s requires the deposit monitor", strategy)
+	}
+
+	return &Selector{
+		strategy: strategy,
+		deposits: deposits,
+		next:     make(map[common.Address]uint64),
+	}, nil
+}
+
+// Select returns one of the candidate paymasters for the EntryPoint. Candidates must not be empty.
+func (s *Selector) Select(ep common.Address, candidates []common.Address) common.Address {
+	switch s.strategy {
+	case RoundRobin:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		i := s.next[ep]
+		s.next[ep] = i + 1
+		return candidates[i%uint64(len(candidates))]
+	case MaxDeposit:
+		best := candidates[0]
+		var bestInfo *deposit.Info
+		for _, pm := range candidates {
+			info := s.deposits.Get(ep, pm)
+			if info != nil && (bestInfo == nil || info.Deposit.Cmp(bestInfo.Deposit) > 0) {
+				best, bestInfo = pm, info
+			}
+		}
+		return best
+	default:
+		return candidates[0]
+	}
+}
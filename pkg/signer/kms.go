@@ -0,0 +1,171 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultKMSURL is the endpoint of Google Cloud KMS.
+var DefaultKMSURL = "https://cloudkms.googleapis.com"
+
+type subjectPublicKeyInfo struct {
+	Algorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.ObjectIdentifier
+	}
+	PublicKey asn1.BitString
+}
+
+type ecdsaSignature struct {
+	R *big.Int
+	S *big.Int
+}
+
+// KMS is a Signer for a secp256k1 key that never leaves a cloud KMS. It uses the Cloud KMS REST API where
+// the public key is returned as PEM and signatures are returned as ASN.1 DER over a given digest.
+type KMS struct {
+	url     string
+	keyName string
+	token   *Token
+	address common.Address
+	http    *http.Client
+}
+
+// NewKMS returns a KMS signer for the key version at keyName. The address is derived from the public key
+// of the key version on creation. If url is empty, DefaultKMSURL is used. Since Cloud KMS access tokens
+// expire, the token should be read from a file that is kept up to date.
+func NewKMS(url string, keyName string, token *Token) (*KMS, error) {
+	if keyName == "" {
+		return nil, errors.New("kms: key name not set")
+	}
+	if url == "" {
+		url = DefaultKMSURL
+	}
+
+	s := &KMS{
+		url:     strings.TrimSuffix(url, "/"),
+		keyName: keyName,
+		token:   token,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+	addr, err := s.fetchAddress()
+	if err != nil {
+		return nil, err
+	}
+	s.address = addr
+
+	return s, nil
+}
+
+func (s *KMS) do(method string, path string, in any, out any) error {
+	var body *bytes.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, s.url+"/v1/"+s.keyName+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := setAuthorization(req, s.token); err != nil {
+		return err
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kms: unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (s *KMS) fetchAddress() (common.Address, error) {
+	var res struct {
+		Pem string `json:"pem"`
+	}
+	if err := s.do(http.MethodGet, "/publicKey", nil, &res); err != nil {
+		return common.Address{}, err
+	}
+
+	block, _ := pem.Decode([]byte(res.Pem))
+	if block == nil {
+		return common.Address{}, errors.New("kms: invalid public key pem")
+	}
+	var spki subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &spki); err != nil {
+		return common.Address{}, err
+	}
+	pub, err := crypto.UnmarshalPubkey(spki.PublicKey.Bytes)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+func (s *KMS) Address() common.Address {
+	return s.address
+}
+
+func (s *KMS) SignMessage(message []byte) ([]byte, error) {
+	digest := accounts.TextHash(message)
+	req := map[string]any{
+		"digest": map[string]string{"sha256": base64.StdEncoding.EncodeToString(digest)},
+	}
+	var res struct {
+		Signature string `json:"signature"`
+	}
+	if err := s.do(http.MethodPost, ":asymmetricSign", req, &res); err != nil {
+		return nil, err
+	}
+
+	der, err := base64.StdEncoding.DecodeString(res.Signature)
+	if err != nil {
+		return nil, err
+	}
+	var sig ecdsaSignature
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, err
+	}
+
+	// Ethereum only accepts signatures with a low S value.
+	if sig.S.Cmp(secp256k1HalfN) > 0 {
+		sig.S = new(big.Int).Sub(secp256k1N, sig.S)
+	}
+
+	// KMS does not return a recovery ID so try both values.
+	out := make([]byte, crypto.SignatureLength)
+	sig.R.FillBytes(out[:32])
+	sig.S.FillBytes(out[32:64])
+	for _, v := range []byte{0, 1} {
+		out[crypto.RecoveryIDOffset] = v
+		if res, err := normalize(message, out, s.address); err == nil {
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: does not recover to %s", ErrBadSignature, s.address.Hex())
+}
@@ -0,0 +1,181 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testKeyName = "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"
+
+var (
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1   = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// kmsServer is a stub of the Cloud KMS REST API. The public key is returned for pub and digests are signed
+// with key.
+type kmsServer struct {
+	t      *testing.T
+	pub    *ecdsa.PublicKey
+	key    *ecdsa.PrivateKey
+	highS  bool
+	tokens []string
+}
+
+func (s *kmsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.tokens = append(s.tokens, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+
+	switch r.URL.Path {
+	case "/v1/" + testKeyName + "/publicKey":
+		var spki subjectPublicKeyInfo
+		spki.Algorithm.Algorithm = oidECPublicKey
+		spki.Algorithm.Parameters = oidSecp256k1
+		spki.PublicKey = asn1.BitString{Bytes: crypto.FromECDSAPub(s.pub), BitLength: 65 * 8}
+		der, err := asn1.Marshal(spki)
+		if err != nil {
+			s.t.Error(err)
+			return
+		}
+		pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+		_ = json.NewEncoder(w).Encode(map[string]string{"pem": string(pemBytes)})
+
+	case "/v1/" + testKeyName + ":asymmetricSign":
+		var req struct {
+			Digest struct {
+				Sha256 string `json:"sha256"`
+			} `json:"digest"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.t.Error(err)
+			return
+		}
+		digest, err := base64.StdEncoding.DecodeString(req.Digest.Sha256)
+		if err != nil {
+			s.t.Error(err)
+			return
+		}
+		sig, err := crypto.Sign(digest, s.key)
+		if err != nil {
+			s.t.Error(err)
+			return
+		}
+
+		// KMS does not normalize S so either value can be returned.
+		es := ecdsaSignature{R: new(big.Int).SetBytes(sig[:32]), S: new(big.Int).SetBytes(sig[32:64])}
+		if s.highS {
+			es.S = new(big.Int).Sub(secp256k1N, es.S)
+		}
+		der, err := asn1.Marshal(es)
+		if err != nil {
+			s.t.Error(err)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"signature": base64.StdEncoding.EncodeToString(der)})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestKMSSignMessage(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	for _, highS := range []bool{false, true} {
+		srv := httptest.NewServer(&kmsServer{t: t, pub: &key.PublicKey, key: key, highS: highS})
+		defer srv.Close()
+
+		s, err := NewKMS(srv.URL, testKeyName, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Address() != addr {
+			t.Fatalf("got address %s, want %s", s.Address().Hex(), addr.Hex())
+		}
+
+		// Sign enough messages that both recovery IDs are likely to be needed.
+		for i := 0; i < 8; i++ {
+			msg := []byte{byte(i)}
+			sig, err := s.SignMessage(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if new(big.Int).SetBytes(sig[32:64]).Cmp(secp256k1HalfN) > 0 {
+				t.Errorf("highS = %t: got high S value", highS)
+			}
+			if sig[crypto.RecoveryIDOffset] != 27 && sig[crypto.RecoveryIDOffset] != 28 {
+				t.Errorf("highS = %t: got recovery ID %d, want 27 or 28", highS, sig[crypto.RecoveryIDOffset])
+			}
+			sig[crypto.RecoveryIDOffset] -= 27
+			pub, err := crypto.SigToPub(accounts.TextHash(msg), sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if crypto.PubkeyToAddress(*pub) != addr {
+				t.Errorf("highS = %t: signature does not recover to %s", highS, addr.Hex())
+			}
+		}
+	}
+}
+
+func TestKMSWrongAddress(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	srv := httptest.NewServer(&kmsServer{t: t, pub: &key.PublicKey, key: other})
+	defer srv.Close()
+
+	s, err := NewKMS(srv.URL, testKeyName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignMessage([]byte("hello")); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("got %v, want %v", err, ErrBadSignature)
+	}
+}
+
+func TestKMSTokenFile(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	stub := &kmsServer{t: t, pub: &key.PublicKey, key: key}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(file, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewKMS(srv.URL, testKeyName, NewToken("", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewrite the token file with a later modification time as an external refresh would.
+	if err := os.WriteFile(file, []byte("second\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignMessage([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stub.tokens) != 2 || stub.tokens[0] != "first" || stub.tokens[1] != "second" {
+		t.Errorf("got tokens %v, want [first second]", stub.tokens)
+	}
+}
@@ -0,0 +1,65 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Local is a Signer that holds the private key in memory.
+type Local struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewLocal returns a Local signer from a hex encoded private key.
+func NewLocal(privateKey string) (*Local, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocalFromKey(key), nil
+}
+
+// NewLocalFromKeystore returns a Local signer from a V3 keystore file encrypted with the password.
+func NewLocalFromKeystore(path string, password string) (*Local, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocalFromKey(key.PrivateKey), nil
+}
+
+// NewLocalFromKey returns a Local signer from a decoded private key.
+func NewLocalFromKey(key *ecdsa.PrivateKey) *Local {
+	return &Local{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+func (s *Local) Address() common.Address {
+	return s.address
+}
+
+func (s *Local) SignMessage(message []byte) ([]byte, error) {
+	sig, err := crypto.Sign(accounts.TextHash(message), s.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+
+	return sig, nil
+}
@@ -0,0 +1,104 @@
+// Package signer provides backends for signing paymaster hashes with the verifier key.
+package signer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrUnknownType is returned when the signer type in Opts is not recognized.
+	ErrUnknownType = errors.New("signer: unknown type")
+
+	// ErrBadSignature is returned when a remote backend returns a signature that does not recover to the
+	// expected address.
+	ErrBadSignature = errors.New("signer: bad signature")
+
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// Signer signs messages with the verifier key of a paymaster.
+type Signer interface {
+	// Address returns the address of the verifier key.
+	Address() common.Address
+
+	// SignMessage returns an EIP-191 signature of the message in the [R || S || V] format where V is 27 or
+	// 28. This is the same format as the eth_sign RPC method.
+	SignMessage(message []byte) ([]byte, error)
+}
+
+// Opts are the options for creating a Signer with New.
+type Opts struct {
+	// Type is one of "local", "web3signer", or "kms".
+	Type string
+
+	// PrivateKey is the hex encoded key for the local backend.
+	PrivateKey string
+
+	// KeystoreFile is the path to a V3 keystore for the local backend. If set, it is used instead of
+	// PrivateKey and is decrypted with KeystorePassword.
+	KeystoreFile     string
+	KeystorePassword string
+
+	// URL is the endpoint of the web3signer and kms backends.
+	URL string
+
+	// Address is the account to sign with on the web3signer backend.
+	Address common.Address
+
+	// KeyName is the full resource name of the key version on the kms backend.
+	KeyName string
+
+	// Token is an optional bearer token for authenticating with remote backends.
+	Token string
+
+	// TokenFile is the path to a file with the bearer token. If set, it is used instead of Token and is read
+	// again whenever the file is modified.
+	TokenFile string
+}
+
+// New returns a Signer for the backend type set in opts.
+func New(opts *Opts) (Signer, error) {
+	switch opts.Type {
+	case "", "local":
+		if opts.KeystoreFile != "" {
+			return NewLocalFromKeystore(opts.KeystoreFile, opts.KeystorePassword)
+		}
+		return NewLocal(opts.PrivateKey)
+	case "web3signer":
+		return NewWeb3Signer(opts.URL, opts.Address, NewToken(opts.Token, opts.TokenFile))
+	case "kms":
+		return NewKMS(opts.URL, opts.KeyName, NewToken(opts.Token, opts.TokenFile))
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, opts.Type)
+	}
+}
+
+// normalize returns a signature with V set to 27 or 28 and checks that it was signed by the address.
+func normalize(message []byte, sig []byte, addr common.Address) ([]byte, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: invalid length %d", ErrBadSignature, len(sig))
+	}
+
+	out := bytes.Clone(sig)
+	if out[crypto.RecoveryIDOffset] >= 27 {
+		out[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(message), out)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadSignature, err)
+	}
+	if crypto.PubkeyToAddress(*pub) != addr {
+		return nil, fmt.Errorf("%w: does not recover to %s", ErrBadSignature, addr.Hex())
+	}
+
+	out[crypto.RecoveryIDOffset] += 27
+	return out, nil
+}
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token is the bearer token for authenticating with a remote backend. If it is read from a file, the file is
+// read again whenever it is modified. This allows short lived tokens, such as Cloud KMS access tokens which
+// expire after an hour, to be refreshed by an external process without restarting the paymaster.
+type Token struct {
+	value string
+	file  string
+
+	mu      sync.Mutex
+	modTime time.Time
+}
+
+// NewToken returns a Token with the given value, or read from file if set. It returns nil if neither is set.
+func NewToken(value string, file string) *Token {
+	if value == "" && file == "" {
+		return nil
+	}
+	return &Token{value: value, file: file}
+}
+
+// Get returns the current value of the token. A nil Token returns an empty string.
+func (t *Token) Get() (string, error) {
+	if t == nil {
+		return "", nil
+	}
+	if t.file == "" {
+		return t.value, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	info, err := os.Stat(t.file)
+	if err != nil {
+		return "", err
+	}
+	if !info.ModTime().Equal(t.modTime) {
+		b, err := os.ReadFile(t.file)
+		if err != nil {
+			return "", err
+		}
+		t.value = strings.TrimSpace(string(b))
+		t.modTime = info.ModTime()
+	}
+	return t.value, nil
+}
+
+// setAuthorization sets the bearer token on the request if one is set.
+func setAuthorization(req *http.Request, token *Token) error {
+	value, err := token.Get()
+	if err != nil {
+		return err
+	}
+	if value != "" {
+		req.Header.Set("Authorization", "Bearer "+value)
+	}
+	return nil
+}
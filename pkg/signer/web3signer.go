@@ -0,0 +1,100 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type rpcRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	Result *hexutil.Bytes `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Web3Signer is a Signer that calls eth_sign on a remote signer that implements the Web3Signer Eth1 API.
+type Web3Signer struct {
+	url     string
+	address common.Address
+	token   *Token
+	http    *http.Client
+}
+
+// NewWeb3Signer returns a Web3Signer for the account at the given URL. The token is optional.
+func NewWeb3Signer(url string, address common.Address, token *Token) (*Web3Signer, error) {
+	if url == "" {
+		return nil, errors.New("web3signer: url not set")
+	}
+	if address == (common.Address{}) {
+		return nil, errors.New("web3signer: address not set")
+	}
+
+	return &Web3Signer{
+		url:     url,
+		address: address,
+		token:   token,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (s *Web3Signer) Address() common.Address {
+	return s.address
+}
+
+func (s *Web3Signer) SignMessage(message []byte) ([]byte, error) {
+	body, err := json.Marshal(&rpcRequest{
+		JsonRpc: "2.0",
+		ID:      1,
+		Method:  "eth_sign",
+		Params:  []any{s.address.Hex(), hexutil.Encode(message)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := setAuthorization(req, s.token); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("web3signer: unexpected status %d", resp.StatusCode)
+	}
+
+	var res rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("web3signer: %s (%d)", res.Error.Message, res.Error.Code)
+	}
+	if res.Result == nil {
+		return nil, errors.New("web3signer: empty result")
+	}
+
+	return normalize(message, *res.Result, s.address)
+}
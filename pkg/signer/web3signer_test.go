@@ -0,0 +1,125 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newWeb3SignerServer returns a stub Web3Signer that signs eth_sign requests with key. The recovery ID of
+// each signature is offset by v.
+func newWeb3SignerServer(t *testing.T, key *ecdsa.PrivateKey, v byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		if req.Method != "eth_sign" || len(req.Params) != 2 {
+			t.Errorf("unexpected request: %+v", req)
+			return
+		}
+		msg, err := hexutil.Decode(req.Params[1].(string))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		sig, err := crypto.Sign(accounts.TextHash(msg), key)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sig[crypto.RecoveryIDOffset] += v
+		res := map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": hexutil.Encode(sig)}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+}
+
+func TestWeb3SignerSignMessage(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	msg := []byte("hello")
+
+	for _, v := range []byte{0, 27} {
+		srv := newWeb3SignerServer(t, key, v)
+		defer srv.Close()
+
+		s, err := NewWeb3Signer(srv.URL, addr, NewToken("secret", ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := s.SignMessage(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig[crypto.RecoveryIDOffset] != 27 && sig[crypto.RecoveryIDOffset] != 28 {
+			t.Errorf("v = %d: got recovery ID %d, want 27 or 28", v, sig[crypto.RecoveryIDOffset])
+		}
+		sig[crypto.RecoveryIDOffset] -= 27
+		pub, err := crypto.SigToPub(accounts.TextHash(msg), sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if crypto.PubkeyToAddress(*pub) != addr {
+			t.Errorf("v = %d: signature does not recover to %s", v, addr.Hex())
+		}
+	}
+}
+
+func TestWeb3SignerWrongAddress(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	srv := newWeb3SignerServer(t, other, 27)
+	defer srv.Close()
+
+	s, err := NewWeb3Signer(srv.URL, crypto.PubkeyToAddress(key.PublicKey), NewToken("secret", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignMessage([]byte("hello")); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("got %v, want %v", err, ErrBadSignature)
+	}
+}
+
+func TestWeb3SignerErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"locked"}}`))
+	}))
+	defer srv.Close()
+
+	key, _ := crypto.GenerateKey()
+	s, err := NewWeb3Signer(srv.URL, crypto.PubkeyToAddress(key.PublicKey), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignMessage([]byte("hello")); err == nil || err.Error() != "web3signer: locked (-32000)" {
+		t.Errorf("got %v, want web3signer: locked (-32000)", err)
+	}
+}
+
+func TestWeb3SignerUnauthorized(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	srv := newWeb3SignerServer(t, key, 27)
+	defer srv.Close()
+
+	s, err := NewWeb3Signer(srv.URL, crypto.PubkeyToAddress(key.PublicKey), NewToken("wrong", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignMessage([]byte("hello")); err == nil {
+		t.Error("got nil, want error")
+	}
+}
@@ -0,0 +1,245 @@
+// Package validation checks a signed op against the paymaster contract on chain before it is returned to a
+// client. This catches a misconfigured verifier key or paymaster address on the first request instead of
+// after the op is rejected by a bundler.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stackup-wallet/stackup-bundler/pkg/userop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/contract"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/packedop"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/rpcerrors"
+)
+
+var (
+	// ErrPaymasterRejected is wrapped by all errors returned when a signed op fails validation by the
+	// paymaster.
+	ErrPaymasterRejected = errors.New("validation: rejected by paymaster")
+
+	// ErrSignatureFailed is also wrapped when the paymaster rejected the signature. This can happen if the
+	// verifier was rotated after it was last refreshed.
+	ErrSignatureFailed = errors.New("validation: signature failed")
+)
+
+// RejectedData is the data field of the JSON-RPC error returned when a signed op fails validation.
+type RejectedData struct {
+	Paymaster common.Address `json:"paymaster"`
+	Reason    string         `json:"reason"`
+}
+
+// Validator calls validatePaymasterUserOp from the EntryPoint with eth_call.
+type Validator struct {
+	eth     *ethclient.Client
+	chainID *big.Int
+}
+
+// New returns a Validator for the given chain.
+func New(eth *ethclient.Client, chain *big.Int) *Validator {
+	return &Validator{
+		eth:     eth,
+		chainID: chain,
+	}
+}
+
+func reject(pm common.Address, format string, a ...any) error {
+	reason := fmt.Sprintf(format, a...)
+	return rpcerrors.New(
+		rpcerrors.CodePaymasterRejected,
+		fmt.Errorf("%w: %s", ErrPaymasterRejected, reason),
+		&RejectedData{Paymaster: pm, Reason: reason},
+	)
+}
+
+func rejectSignature(pm common.Address, format string, a ...any) error {
+	reason := fmt.Sprintf(format, a...)
+	return rpcerrors.New(
+		rpcerrors.CodePaymasterRejected,
+		fmt.Errorf("%w: %w: %s", ErrPaymasterRejected, ErrSignatureFailed, reason),
+		&RejectedData{Paymaster: pm, Reason: reason},
+	)
+}
+
+// revertReason returns the reason of a reverted eth_call. It returns false if the call failed for any other
+// reason, such as the node being unavailable.
+func revertReason(err error) (string, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) || dataErr.ErrorData() == nil {
+		return "", false
+	}
+
+	data, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err.Error(), true
+	}
+	b, decodeErr := hexutil.Decode(data)
+	if decodeErr != nil {
+		return err.Error(), true
+	}
+	if reason, unpackErr := abi.UnpackRevert(b); unpackErr == nil {
+		return reason, true
+	}
+	return data, true
+}
+
+// check returns an error if the validationData from the paymaster has a signature failure or a validity
+// window that is expired or does not match the signed data.
+func (v *Validator) check(
+	out []any,
+	hash [32]byte,
+	data *contract.Data,
+	sig []byte,
+) error {
+	if len(out) != 2 {
+		return fmt.Errorf("validation: unexpected output length %d", len(out))
+	}
+	vd, ok := out[1].(*big.Int)
+	if !ok {
+		return errors.New("validation: cannot assert type: validationData is not of type *big.Int")
+	}
+
+	// validationData is packed as validAfter (6 bytes), validUntil (6 bytes), and sigFailed (20 bytes).
+	b := common.LeftPadBytes(vd.Bytes(), 32)
+	validAfter := big.NewInt(0).SetBytes(b[0:6])
+	validUntil := big.NewInt(0).SetBytes(b[6:12])
+	sigFailed := big.NewInt(0).SetBytes(b[12:32]).Sign() != 0
+
+	if sigFailed {
+		c, err := contract.NewContract(data.Paymaster, v.eth)
+		if err != nil {
+			return err
+		}
+		verifier, err := c.Verifier(&bind.CallOpts{})
+		if err != nil {
+			return err
+		}
+		if len(sig) != crypto.SignatureLength {
+			return rejectSignature(data.Paymaster, "signature has invalid length %d", len(sig))
+		}
+		rsv := common.CopyBytes(sig)
+		if rsv[crypto.RecoveryIDOffset] >= 27 {
+			rsv[crypto.RecoveryIDOffset] -= 27
+		}
+		pub, err := crypto.SigToPub(accounts.TextHash(hash[:]), rsv)
+		if err != nil {
+			return rejectSignature(data.Paymaster, "signature can't be recovered: %s", err)
+		}
+		if signer := crypto.PubkeyToAddress(*pub); signer != verifier {
+			return rejectSignature(
+				data.Paymaster,
+				"signature recovers to %s but verifier is %s",
+				signer.Hex(),
+				verifier.Hex(),
+			)
+		}
+		return rejectSignature(
+			data.Paymaster,
+			"signature from verifier rejected, hash does not match the contract",
+		)
+	}
+
+	if validAfter.Cmp(data.ValidAfter) != 0 || validUntil.Cmp(data.ValidUntil) != 0 {
+		return reject(
+			data.Paymaster,
+			"validity window [%s, %s] does not match signed window [%s, %s]",
+			validAfter,
+			validUntil,
+			data.ValidAfter,
+			data.ValidUntil,
+		)
+	}
+	if validUntil.Sign() != 0 && validUntil.Int64() <= time.Now().Unix() {
+		return reject(data.Paymaster, "validUntil %s has expired", validUntil)
+	}
+	return nil
+}
+
+// Validate checks that the paymasterAndData of a signed op will be accepted by validatePaymasterUserOp on
+// the given EntryPoint. The signature must recover to the paymaster's verifier and the validity window must
+// not have expired. A nil Validator will accept all ops.
+func (v *Validator) Validate(op *userop.UserOperation, ep common.Address) error {
+	if v == nil {
+		return nil
+	}
+
+	data, sig, err := contract.DecodePaymasterAndData(op.PaymasterAndData)
+	if err != nil {
+		return err
+	}
+	hash, err := contract.ComputeHash(op, v.chainID, data)
+	if err != nil {
+		return err
+	}
+	c, err := contract.NewContract(data.Paymaster, v.eth)
+	if err != nil {
+		return err
+	}
+
+	var out []any
+	raw := &contract.ContractCallerRaw{Contract: &c.ContractCaller}
+	err = raw.Call(
+		&bind.CallOpts{From: ep},
+		&out,
+		"validatePaymasterUserOp",
+		contract.UserOperation(*op),
+		op.GetUserOpHash(ep, v.chainID),
+		op.GetMaxPrefund(),
+	)
+	if reason, ok := revertReason(err); ok {
+		return reject(data.Paymaster, "validatePaymasterUserOp reverted: %s", reason)
+	} else if err != nil {
+		return err
+	}
+
+	return v.check(out, hash, data, sig)
+}
+
+// ValidateV07 is the EntryPoint v0.7 equivalent of Validate.
+func (v *Validator) ValidateV07(op *packedop.UserOperation, ep common.Address) error {
+	if v == nil {
+		return nil
+	}
+
+	data, sig, err := contract.DecodePaymasterAndData(append(op.Paymaster.Bytes(), op.PaymasterData...))
+	if err != nil {
+		return err
+	}
+	packed := op.Pack()
+	hash, err := contract.ComputeHashV07(packed, v.chainID, data)
+	if err != nil {
+		return err
+	}
+	c, err := contract.NewContractV07(data.Paymaster, v.eth)
+	if err != nil {
+		return err
+	}
+
+	var out []any
+	raw := &contract.ContractV07CallerRaw{Contract: &c.ContractV07Caller}
+	err = raw.Call(
+		&bind.CallOpts{From: ep},
+		&out,
+		"validatePaymasterUserOp",
+		packed,
+		op.GetUserOpHash(ep, v.chainID),
+		op.GetMaxPrefund(),
+	)
+	if reason, ok := revertReason(err); ok {
+		return reject(data.Paymaster, "validatePaymasterUserOp reverted: %s", reason)
+	} else if err != nil {
+		return err
+	}
+
+	return v.check(out, hash, data, sig)
+}
@@ -0,0 +1,109 @@
+package watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stackup-wallet/stackup-paymaster/internal/dbutils"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/budget"
+)
+
+// Status is the state of a sponsored UserOperation.
+type Status string
+
+const (
+	// Pending means the UserOperation was signed but has not been seen on chain.
+	Pending Status = "pending"
+
+	// Included means a UserOperationEvent was emitted for the UserOperation.
+	Included Status = "included"
+
+	// Expired means the paymaster signature expired before the UserOperation was seen on chain.
+	Expired Status = "expired"
+)
+
+var (
+	keyPrefix        = dbutils.JoinValues("watcher")
+	recordPrefix     = dbutils.JoinValues(keyPrefix, "record")
+	pendingPrefix    = dbutils.JoinValues(keyPrefix, "pending")
+	lastBlockKey     = []byte(dbutils.JoinValues(keyPrefix, "lastBlock"))
+	errRecordMissing = errors.New("watcher: record not found")
+)
+
+// Record is the accounting entry for a single sponsored UserOperation.
+type Record struct {
+	UserOpHash  common.Hash         `json:"userOpHash"`
+	EntryPoint  common.Address      `json:"entryPoint"`
+	Paymaster   common.Address      `json:"paymaster"`
+	Sender      common.Address      `json:"sender"`
+	Nonce       *big.Int            `json:"nonce"`
+	APIKey      string              `json:"apiKey,omitempty"`
+	PolicyID    string              `json:"policyId,omitempty"`
+	MaxCost     *big.Int            `json:"maxCost"`
+	ValidUntil  int64               `json:"validUntil"`
+	SignedAt    int64               `json:"signedAt"`
+	Reservation *budget.Reservation `json:"reservation,omitempty"`
+
+	Status        Status      `json:"status"`
+	Success       bool        `json:"success"`
+	ActualGasCost *big.Int    `json:"actualGasCost,omitempty"`
+	ActualGasUsed *big.Int    `json:"actualGasUsed,omitempty"`
+	TxHash        common.Hash `json:"txHash,omitempty"`
+	BlockNumber   uint64      `json:"blockNumber,omitempty"`
+}
+
+func getRecordKey(hash common.Hash) []byte {
+	return []byte(dbutils.JoinValues(recordPrefix, hash.String()))
+}
+
+func getPendingKey(hash common.Hash) []byte {
+	return []byte(dbutils.JoinValues(pendingPrefix, hash.String()))
+}
+
+func saveRecord(txn *badger.Txn, r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := txn.Set(getRecordKey(r.UserOpHash), data); err != nil {
+		return err
+	}
+
+	if r.Status == Pending {
+		return txn.Set(getPendingKey(r.UserOpHash), nil)
+	}
+	return txn.Delete(getPendingKey(r.UserOpHash))
+}
+
+func getRecord(txn *badger.Txn, hash common.Hash) (*Record, error) {
+	item, err := txn.Get(getRecordKey(hash))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, errRecordMissing
+	} else if err != nil {
+		return nil, err
+	}
+
+	var r Record
+	err = item.Value(func(val []byte) error {
+		return json.Unmarshal(val, &r)
+	})
+	return &r, err
+}
+
+func getPendingHashes(txn *badger.Txn) []common.Hash {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	prefix := []byte(pendingPrefix + ":")
+	hashes := []common.Hash{}
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		key := string(it.Item().Key())
+		hashes = append(hashes, common.HexToHash(key[len(prefix):]))
+	}
+	return hashes
+}
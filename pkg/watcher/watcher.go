@@ -0,0 +1,306 @@
+// Package watcher reconciles sponsored UserOperations with the UserOperationEvent logs emitted by each
+// EntryPoint. If the node supports subscriptions, events are reconciled as soon as they are emitted. The
+// watcher also polls for logs at a fixed interval so that events missed by a dropped subscription, or by a
+// node without subscriptions, are still reconciled and expired ops are released.
+package watcher
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/go-logr/logr"
+	"github.com/stackup-wallet/stackup-bundler/pkg/entrypoint"
+	"github.com/stackup-wallet/stackup-paymaster/pkg/budget"
+)
+
+// The max number of blocks to query for logs in a single request.
+var maxBlockRange = uint64(1000)
+
+// Watcher follows UserOperationEvent logs from each EntryPoint for all configured paymasters and
+// reconciles them with the UserOperations that were signed by this service.
+type Watcher struct {
+	db      *badger.DB
+	eth     *ethclient.Client
+	ep2pms  map[common.Address][]common.Address
+	budgets *budget.Tracker
+	logger  logr.Logger
+}
+
+// New returns a Watcher that persists records to the given DB. Unused budget reservations are released
+// back to the budget Tracker once the actual gas cost of an op is known.
+func New(
+	db *badger.DB,
+	eth *ethclient.Client,
+	ep2pms map[common.Address][]common.Address,
+	budgets *budget.Tracker,
+	l logr.Logger,
+) *Watcher {
+	return &Watcher{
+		db:      db,
+		eth:     eth,
+		ep2pms:  ep2pms,
+		budgets: budgets,
+		logger:  l,
+	}
+}
+
+// Track saves a record of a signed UserOperation as pending. A nil Watcher is a no-op.
+func (w *Watcher) Track(r *Record) error {
+	if w == nil {
+		return nil
+	}
+
+	r.Status = Pending
+	return w.db.Update(func(txn *badger.Txn) error {
+		return saveRecord(txn, r)
+	})
+}
+
+// Get returns the record of a sponsored UserOperation by its userOpHash.
+func (w *Watcher) Get(hash common.Hash) (*Record, error) {
+	var r *Record
+	err := w.db.View(func(txn *badger.Txn) error {
+		var err error
+		r, err = getRecord(txn, hash)
+		return err
+	})
+	return r, err
+}
+
+// Run subscribes to new logs if the node supports it and polls for new logs at the given interval in a
+// separate goroutine.
+func (w *Watcher) Run(interval time.Duration) {
+	for ep, pms := range w.ep2pms {
+		if err := w.subscribe(ep, pms); errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			w.logger.Info("watcher subscriptions unsupported, polling only", "entrypoint", ep.String())
+		} else if err != nil {
+			w.logger.Error(err, "watcher subscribe error", "entrypoint", ep.String())
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := w.poll(); err != nil {
+				w.logger.Error(err, "watcher error")
+			}
+		}
+	}()
+}
+
+// subscribe reconciles UserOperationEvent logs from the EntryPoint as they are emitted. The subscription is
+// not renewed if it fails since polling will reconcile any events that were missed.
+func (w *Watcher) subscribe(ep common.Address, pms []common.Address) error {
+	f, err := entrypoint.NewEntrypointFilterer(ep, w.eth)
+	if err != nil {
+		return err
+	}
+
+	sink := make(chan *entrypoint.EntrypointUserOperationEvent)
+	sub, err := f.WatchUserOperationEvent(&bind.WatchOpts{}, sink, nil, nil, pms)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-sink:
+				if err := w.reconcile(ev); err != nil {
+					w.logger.Error(err, "watcher error")
+				}
+			case err := <-sub.Err():
+				if err != nil {
+					w.logger.Error(err, "watcher subscription error", "entrypoint", ep.String())
+				}
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (w *Watcher) getLastBlock() (uint64, bool, error) {
+	var last uint64
+	err := w.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(lastBlockKey)
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			last = big.NewInt(0).SetBytes(val).Uint64()
+			return nil
+		})
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return 0, false, nil
+	}
+	return last, err == nil, err
+}
+
+func (w *Watcher) poll() error {
+	latest, err := w.eth.BlockNumber(context.Background())
+	if err != nil {
+		return err
+	}
+	last, ok, err := w.getLastBlock()
+	if err != nil {
+		return err
+	}
+	if !ok && latest > 0 {
+		last = latest - 1
+	}
+	if last >= latest {
+		return nil
+	}
+
+	from := last + 1
+	to := min(latest, from+maxBlockRange-1)
+	for ep, pms := range w.ep2pms {
+		f, err := entrypoint.NewEntrypointFilterer(ep, w.eth)
+		if err != nil {
+			return err
+		}
+
+		it, err := f.FilterUserOperationEvent(&bind.FilterOpts{Start: from, End: &to}, nil, nil, pms)
+		if err != nil {
+			return err
+		}
+		for it.Next() {
+			if err := w.reconcile(it.Event); err != nil {
+				_ = it.Close()
+				return err
+			}
+		}
+		if err := it.Close(); err != nil {
+			return err
+		}
+	}
+
+	head, err := w.eth.HeaderByNumber(context.Background(), big.NewInt(0).SetUint64(to))
+	if err != nil {
+		return err
+	}
+	if err := w.expire(int64(head.Time)); err != nil {
+		return err
+	}
+
+	return w.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(lastBlockKey, big.NewInt(0).SetUint64(to).Bytes())
+	})
+}
+
+// reconcile updates the record of a sponsored op with its actual gas cost and releases any unused amount
+// of its budget reservation. An op that was already reconciled is skipped.
+func (w *Watcher) reconcile(ev *entrypoint.EntrypointUserOperationEvent) error {
+	var r *Record
+	err := w.db.Update(func(txn *badger.Txn) error {
+		var err error
+		r, err = getRecord(txn, ev.UserOpHash)
+		if err != nil {
+			return err
+		}
+		if r.Status == Included {
+			r = nil
+			return nil
+		}
+
+		r.Status = Included
+		r.Success = ev.Success
+		r.ActualGasCost = ev.ActualGasCost
+		r.ActualGasUsed = ev.ActualGasUsed
+		r.TxHash = ev.Raw.TxHash
+		r.BlockNumber = ev.Raw.BlockNumber
+		return saveRecord(txn, r)
+	})
+	if errors.Is(err, errRecordMissing) {
+		w.logger.Info(
+			"watcher unknown op",
+			"user_op_hash", common.Hash(ev.UserOpHash).String(),
+			"paymaster", ev.Paymaster.String(),
+		)
+		return nil
+	} else if err != nil || r == nil {
+		return err
+	}
+
+	if r.Reservation != nil {
+		unused := big.NewInt(0).Sub(r.Reservation.Amount, ev.ActualGasCost)
+		if unused.Sign() > 0 {
+			if err := w.budgets.Release(r.Reservation, unused); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.logger.Info(
+		"watcher op included",
+		"user_op_hash", r.UserOpHash.String(),
+		"sender", r.Sender.String(),
+		"paymaster", r.Paymaster.String(),
+		"api_key", r.APIKey,
+		"success", r.Success,
+		"actual_gas_cost", r.ActualGasCost.String(),
+		"tx_hash", r.TxHash.String(),
+	)
+	return nil
+}
+
+// expire marks all pending records with a validUntil before the given timestamp as expired and releases
+// their full budget reservation.
+func (w *Watcher) expire(timestamp int64) error {
+	var hashes []common.Hash
+	if err := w.db.View(func(txn *badger.Txn) error {
+		hashes = getPendingHashes(txn)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		var r *Record
+		if err := w.db.Update(func(txn *badger.Txn) error {
+			var err error
+			r, err = getRecord(txn, hash)
+			if errors.Is(err, errRecordMissing) {
+				r = nil
+				return txn.Delete(getPendingKey(hash))
+			}
+			if err != nil || r.Status != Pending || r.ValidUntil >= timestamp {
+				r = nil
+				return err
+			}
+
+			r.Status = Expired
+			return saveRecord(txn, r)
+		}); err != nil {
+			return err
+		}
+		if r == nil {
+			continue
+		}
+
+		if r.Reservation != nil {
+			if err := w.budgets.Release(r.Reservation, r.Reservation.Amount); err != nil {
+				return err
+			}
+		}
+		w.logger.Info(
+			"watcher op expired",
+			"user_op_hash", r.UserOpHash.String(),
+			"sender", r.Sender.String(),
+			"api_key", r.APIKey,
+		)
+	}
+	return nil
+}